@@ -1,28 +1,85 @@
 package main
 
-const (
-	stand = "s"
-	hit   = "h"
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
 )
 
 func main() {
-	var gs GameState
-	gs = Shuffle(gs)
-	gs = Deal(gs)
+	decks := flag.Int("decks", 3, "the number of decks in the shoe")
+	hands := flag.Int("hands", 100, "the most hands to play")
+	bankroll := flag.Int("bankroll", 1000, "the chips you start with (0 for unlimited)")
+	minBet := flag.Int("min-bet", 10, "the table minimum bet")
+	maxBet := flag.Int("max-bet", 0, "the table maximum bet (0 for no maximum)")
+	seed := flag.Int64("seed", 0, "the seed to shuffle with, to replay a previous game (default random)")
+	flag.Parse()
 
-	for {
-		if gs.Phase != PlayerTurn {
-			break
+	opts := blackjack.Options{
+		NDecks:        *decks,
+		NHands:        *hands,
+		MinBet:        *minBet,
+		MaxBet:        *maxBet,
+		Bankroll:      *bankroll,
+		Insurance:     true,
+		LateSurrender: true,
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts.Seed = seed
 		}
-		gs = TakePlayerTurn(gs)
+	})
+
+	restore, err := rawMode()
+	raw := err == nil
+	if raw {
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		go func() {
+			<-interrupts
+			restore()
+			os.Exit(1)
+		}()
+	}
+	balance, err := play(opts, newKeyReader(os.Stdin, raw), os.Stdout, raw)
+	if raw {
+		restore()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+		os.Exit(1)
 	}
 
-	for {
-		if gs.Phase != DealerTurn {
-			break
-		}
-		gs = TakeDealerTurn(gs)
+	if *bankroll > 0 {
+		fmt.Printf("You leave the table with %d chips (%+d).\n", *bankroll+balance, balance)
+	} else {
+		fmt.Printf("You leave the table %+d.\n", balance)
 	}
+}
 
-	gs = EndHand(gs)
+// play plays the game for the person at the keyboard, drawing the table
+// to out, until they leave the table or the game is over, and returns
+// their balance. If clear is set, the screen is cleared before each
+// drawing.
+func play(opts blackjack.Options, keys *keyReader, out io.Writer, clear bool) (int, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	t := newTUI(opts, keys, out, clear, cancel)
+	if !t.promptBet() {
+		return 0, nil
+	}
+
+	g := blackjack.New(opts)
+	g.Observe(t)
+	balance, err := g.PlayContext(ctx, t)
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	return balance, err
 }
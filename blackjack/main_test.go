@@ -1 +1,89 @@
 package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// playKeys plays a game pressing the keys, one per line, returning the
+// balance and everything drawn.
+func playKeys(t *testing.T, opts blackjack.Options, keys ...string) (int, string) {
+	t.Helper()
+	renderer.Colour = false
+	var out bytes.Buffer
+	in := strings.NewReader(strings.Join(keys, "\n") + "\n")
+	balance, err := play(opts, newKeyReader(in, false), &out, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return balance, out.String()
+}
+
+func TestPlay(t *testing.T) {
+	// The player raises the bet, asks for a hint and stands on 19
+	// against the dealer's 17, then leaves.
+	opts := blackjack.Options{NHands: 10, MinBet: 10, Bankroll: 1000, Shuffle: deck.Stack("10S 7H 9D 10C")}
+	balance, out := playKeys(t, opts, "+", "", "?", "s", "q")
+	if balance != 20 {
+		t.Errorf("balance is %d, want 20", balance)
+	}
+	for _, want := range []string{"Basic strategy says stand.", "#1  Won +20 against 17", "Chips: 1020 (+20)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPlaySplit(t *testing.T) {
+	// The player splits eights against 19, standing on 18 and hitting 11
+	// to 21.
+	opts := blackjack.Options{NHands: 10, MinBet: 10, Shuffle: deck.Stack("8S 10H 8D 9C 10S 3D 10D")}
+	balance, out := playKeys(t, opts, "", "p", "s", "h", "q")
+	if balance != 0 {
+		t.Errorf("balance is %d, want 0", balance)
+	}
+	for _, want := range []string{"> Hand 1: 18", "> Hand 2: 11", "#1  Lost/Won +0 against 19"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPlayOutOfChips(t *testing.T) {
+	// The player can't afford to double 11, so stands and loses the last
+	// of their chips.
+	opts := blackjack.Options{NHands: 10, MinBet: 10, Bankroll: 15, Shuffle: deck.Stack("6S 10H 5D 7C")}
+	balance, out := playKeys(t, opts, "", "d", "s")
+	if balance != -10 {
+		t.Errorf("balance is %d, want -10", balance)
+	}
+	for _, want := range []string{"not enough chips to double", "You're out of chips."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPlayHint(t *testing.T) {
+	// Basic strategy surrenders 16 against a ten only where the table
+	// allows it.
+	testCases := []struct {
+		opts blackjack.Options
+		want string
+	}{
+		{blackjack.Options{}, "Basic strategy says hit."},
+		{blackjack.Options{LateSurrender: true}, "Basic strategy says surrender."},
+	}
+	for _, tc := range testCases {
+		tc.opts.NHands, tc.opts.MinBet = 10, 10
+		tc.opts.Shuffle = deck.Stack("10S 10H 6D 7C")
+		_, out := playKeys(t, tc.opts, "", "?", "s", "q")
+		if !strings.Contains(out, tc.want) {
+			t.Errorf("late surrender %t: output is missing %q:\n%s", tc.opts.LateSurrender, tc.want, out)
+		}
+	}
+}
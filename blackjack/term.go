@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// rawMode puts the terminal into cbreak mode, in which keys are read as
+// they are pressed and aren't echoed, returning a function that
+// restores it. It fails if stdin isn't a terminal.
+func rawMode() (restore func(), err error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("cbreak", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() {
+		stty(strings.TrimSpace(state))
+	}, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// keyReader reads the keys the player presses. In raw mode each byte is
+// a key; otherwise each line is, by its first character, so that keys
+// can be typed followed by enter. Either way, enter alone is read as
+// '\n'.
+type keyReader struct {
+	r   *bufio.Reader
+	raw bool
+}
+
+func newKeyReader(r io.Reader, raw bool) *keyReader {
+	return &keyReader{r: bufio.NewReader(r), raw: raw}
+}
+
+// key returns the next key pressed.
+func (k *keyReader) key() (byte, error) {
+	if k.raw {
+		b, err := k.r.ReadByte()
+		if b == '\r' {
+			b = '\n'
+		}
+		return b, err
+	}
+	line, err := k.r.ReadString('\n')
+	if err != nil && line == "" {
+		return 0, err
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return '\n', nil
+	}
+	return line[0], nil
+}
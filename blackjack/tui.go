@@ -0,0 +1,354 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/strategy"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// renderer draws cards at the table, in colour unless the NO_COLOR
+// environment variable is set.
+var renderer = deck.Renderer{Colour: os.Getenv("NO_COLOR") == ""}
+
+// Keys offered at each prompt.
+const (
+	betKeys       = "[+/-] change bet  [enter] deal  [q] quit"
+	moveKeys      = "[h] hit  [s] stand  [d] double  [p] split  [r] surrender  [?] hint  [q] quit after this hand"
+	insuranceKeys = "[y] yes  [n] no"
+)
+
+// historyLines is the number of past rounds shown below the table.
+const historyLines = 5
+
+// tui plays a seat for the person at the keyboard, drawing the table in
+// the terminal. It observes the game to follow every card dealt to its
+// hands, including those split from them.
+type tui struct {
+	keys  *keyReader
+	out   io.Writer
+	clear bool // clear the screen before drawing the table
+	opts  blackjack.Options
+	chart *strategy.Chart
+	stop  func() // ends the game once the round in play is settled
+	quit  bool
+
+	// advisor plays the round alongside the player by basic strategy,
+	// following its own advice, to hint at each move.
+	advisor *strategy.StrategyAI
+	before  strategy.StrategyAI // the advisor before its last advice
+	advice  blackjack.Move
+	move    blackjack.Move // the player's last move
+
+	balance int
+	bet     int // the bet for the next round
+	round   int
+	hands   [][]deck.Card // the player's hands this round
+	current int           // the index in hands of the hand being played
+	split   int           // the index in hands of the pair being split, or -1
+	dealer  []deck.Card
+	settled bool // the round is over, and the dealer's hole card shown
+
+	shuffled bool   // the shoe was shuffled for the round
+	message  string // shown below the table
+	history  []string
+}
+
+func newTUI(opts blackjack.Options, keys *keyReader, out io.Writer, clear bool, stop func()) *tui {
+	opts = opts.WithDefaults()
+	return &tui{
+		keys:  keys,
+		out:   out,
+		clear: clear,
+		opts:  opts,
+		chart: strategy.ChartFor(opts),
+		stop:  stop,
+		bet:   opts.MinBet,
+		split: -1,
+	}
+}
+
+// end leaves the table once the round in play is over.
+func (t *tui) end() {
+	t.quit = true
+	t.stop()
+}
+
+// chips returns the chips the player has left, if they have a bankroll.
+func (t *tui) chips() int {
+	return t.opts.Bankroll + t.balance
+}
+
+// canBet reports whether the player has the chips to bet again.
+func (t *tui) canBet() bool {
+	return t.opts.Bankroll == 0 || t.chips() >= t.opts.MinBet
+}
+
+// fitBet brings the bet within the table limits and the player's chips.
+func (t *tui) fitBet() {
+	if t.opts.MaxBet > 0 && t.bet > t.opts.MaxBet {
+		t.bet = t.opts.MaxBet
+	}
+	if t.opts.Bankroll > 0 && t.bet > t.chips() {
+		t.bet = t.chips()
+	}
+	if t.bet < t.opts.MinBet {
+		t.bet = t.opts.MinBet
+	}
+}
+
+// promptBet asks the player for their next bet, returning false if they
+// would rather leave the table.
+func (t *tui) promptBet() bool {
+	for {
+		t.fitBet()
+		t.render(betKeys)
+		k, err := t.keys.key()
+		if err != nil {
+			return false
+		}
+		switch k {
+		case '+', '=':
+			t.bet += t.opts.MinBet
+		case '-', '_':
+			t.bet -= t.opts.MinBet
+		case '\n', ' ':
+			return true
+		case 'q':
+			return false
+		}
+	}
+}
+
+// Bet returns the bet the player chose when the last round was settled.
+func (t *tui) Bet(shuffled bool) int {
+	t.message = ""
+	t.fitBet()
+	t.advisor, t.move = strategy.NewAI(t.chart, t.opts), nil
+	t.advisor.Bet(shuffled)
+	return t.bet
+}
+
+func (t *tui) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	for i := t.current; i < len(t.hands); i++ {
+		if sameCards(t.hands[i], hand) {
+			t.current = i
+			break
+		}
+	}
+	t.before = *t.advisor
+	t.advice = t.advisor.Play(hand, dealer)
+	for {
+		t.render(moveKeys)
+		k, err := t.keys.key()
+		if err != nil {
+			t.end()
+			return blackjack.MoveStand
+		}
+		t.message = ""
+		t.move = nil
+		switch k {
+		case 'h':
+			t.move = blackjack.MoveHit
+		case 's':
+			t.move = blackjack.MoveStand
+		case 'd':
+			t.move = blackjack.MoveDouble
+		case 'p':
+			t.split = t.current
+			t.move = blackjack.MoveSplit
+		case 'r':
+			t.move = blackjack.MoveSurrender
+		case '?':
+			t.message = "Basic strategy says " + blackjack.MoveName(t.advice) + "."
+		case 'q':
+			t.end()
+			t.message = "You'll leave the table once this round is over."
+		}
+		if t.move != nil {
+			return t.move
+		}
+	}
+}
+
+// Rejected explains why the player's move isn't allowed. The advisor
+// takes the rejection too if it would have made the same move, and
+// otherwise takes back its advice, to be asked again.
+func (t *tui) Rejected(err error) {
+	t.message = strings.TrimPrefix(err.Error(), "blackjack: ")
+	t.split = -1
+	switch {
+	case t.move == nil:
+		// the bet was rejected
+	case blackjack.MoveName(t.move) == blackjack.MoveName(t.advice):
+		t.advisor.Rejected(err)
+	default:
+		*t.advisor = t.before
+	}
+}
+
+func (t *tui) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	t.message = fmt.Sprintf("The dealer shows an ace. Take insurance for %d?", t.bet/2)
+	if blackjack.Blackjack(hand...) {
+		t.message = "The dealer shows an ace. Take even money on your blackjack?"
+	}
+	for {
+		t.render(insuranceKeys)
+		k, err := t.keys.key()
+		if err != nil {
+			t.end()
+			return false
+		}
+		switch k {
+		case 'y', 'n':
+			t.message = ""
+			return k == 'y'
+		}
+	}
+}
+
+func (t *tui) Outcome(hand [][]deck.Card, dealer []deck.Card) {
+	// noop: the hands are settled when the game is observed
+}
+
+// Observe follows the cards dealt to the player and the dealer, and
+// settles the round.
+func (t *tui) Observe(e blackjack.Event) {
+	switch e.Kind {
+	case blackjack.Reshuffled:
+		t.shuffled = true
+	case blackjack.HandStarted:
+		t.round, t.hands, t.dealer = e.Round, nil, nil
+		t.current, t.settled = 0, false
+	case blackjack.CardDealt:
+		if e.Seat == blackjack.DealerSeat {
+			t.dealer = append(t.dealer, e.Card)
+			return
+		}
+		t.splitHand()
+		for len(t.hands) <= e.Hand {
+			t.hands = append(t.hands, nil)
+		}
+		t.hands[e.Hand] = append(t.hands[e.Hand], e.Card)
+	case blackjack.HandSettled:
+		t.settle(*e.Result)
+	}
+}
+
+// splitHand splits the pair the player chose to split, which the game
+// does before dealing the next card.
+func (t *tui) splitHand() {
+	if t.split < 0 {
+		return
+	}
+	pair := t.hands[t.split]
+	hands := make([][]deck.Card, 0, len(t.hands)+1)
+	hands = append(hands, t.hands[:t.split]...)
+	hands = append(hands, pair[:1:1], pair[1:2:2])
+	hands = append(hands, t.hands[t.split+1:]...)
+	t.hands, t.split = hands, -1
+}
+
+// settle shows how the round was settled, and asks for the next bet if
+// the game goes on.
+func (t *tui) settle(r blackjack.Result) {
+	t.settled, t.shuffled = true, false
+	t.dealer = r.Dealer
+	t.hands = nil
+	outcomes := make([]string, len(r.Hands))
+	for i, h := range r.Hands {
+		t.hands = append(t.hands, h.Cards)
+		outcomes[i] = h.Outcome.String()
+	}
+	t.balance += r.Net
+
+	summary := fmt.Sprintf("%s %+d", strings.Join(outcomes, "/"), r.Net)
+	if r.Insurance != 0 {
+		summary += fmt.Sprintf(" (insurance %+d)", r.Insurance)
+	}
+	t.history = append(t.history, fmt.Sprintf("#%d  %s against %d", t.round, summary, blackjack.Score(r.Dealer...)))
+	t.message = summary
+
+	switch {
+	case !t.canBet():
+		t.message += ". You're out of chips."
+		t.render("")
+	case t.quit || t.round == t.opts.NHands:
+		t.render("")
+	case !t.promptBet():
+		t.end()
+	}
+}
+
+// render draws the table, followed by the keys the player may press.
+func (t *tui) render(keys string) {
+	var b strings.Builder
+	if t.clear {
+		b.WriteString("\x1b[H\x1b[2J")
+	}
+	if t.round > 0 {
+		fmt.Fprintf(&b, "Round %d of %d   ", t.round, t.opts.NHands)
+	}
+	if t.opts.Bankroll > 0 {
+		fmt.Fprintf(&b, "Chips: %d (%+d)   Bet: %d\n\n", t.chips(), t.balance, t.bet)
+	} else {
+		fmt.Fprintf(&b, "Net: %+d   Bet: %d\n\n", t.balance, t.bet)
+	}
+
+	if len(t.dealer) > 0 {
+		down := 0
+		if !t.settled && len(t.dealer) == 1 && !t.opts.NoHoleCard {
+			down = 1
+		}
+		fmt.Fprintf(&b, "Dealer: %d\n%s\n\n", blackjack.Score(t.dealer...), renderer.HiddenArt(t.dealer, down))
+	}
+	for i, h := range t.hands {
+		label := "You"
+		if len(t.hands) > 1 {
+			label = fmt.Sprintf("Hand %d", i+1)
+			if i == t.current && !t.settled {
+				label = "> " + label
+			}
+		}
+		fmt.Fprintf(&b, "%s: %d\n%s\n\n", label, blackjack.Score(h...), renderer.Art(h...))
+	}
+
+	if t.shuffled && !t.settled {
+		b.WriteString("The dealer has shuffled the shoe.\n")
+	}
+	if t.message != "" {
+		b.WriteString(t.message + "\n")
+	}
+	if len(t.history) > 0 {
+		b.WriteString("\nRecent hands:\n")
+		from := len(t.history) - historyLines
+		if from < 0 {
+			from = 0
+		}
+		for i := len(t.history) - 1; i >= from; i-- {
+			b.WriteString("  " + t.history[i] + "\n")
+		}
+	}
+	if keys != "" {
+		b.WriteString("\n" + keys + "\n")
+	}
+	fmt.Fprint(t.out, b.String())
+}
+
+// sameCards reports whether a and b hold the same cards in the same
+// order.
+func sameCards(a, b []deck.Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,517 @@
+// Package analysis calculates the exact odds of blackjack hands from
+// the composition of the cards left in the shoe, for use in generating
+// and checking strategies.
+package analysis
+
+import (
+	"errors"
+	"math"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// Composition counts the cards of each blackjack value left in the
+// shoe: aces at index 0, twos to nines at indexes 1 to 8 and tens and
+// face cards at index 9.
+type Composition [values]int
+
+const (
+	values = 10
+	ace    = 0
+	ten    = 9
+)
+
+// Shoe returns the composition of a full shoe of the given number of
+// decks.
+func Shoe(decks int) Composition {
+	var c Composition
+	for v := range c {
+		c[v] = 4 * decks
+	}
+	c[ten] = 16 * decks
+	return c
+}
+
+// NewComposition returns the composition of the cards. Jokers and
+// invalid cards are ignored.
+func NewComposition(cards ...deck.Card) Composition {
+	var c Composition
+	for _, card := range cards {
+		if valid(card) {
+			c[valueIndex(card)]++
+		}
+	}
+	return c
+}
+
+// ErrInvalidCard is returned for Jokers and cards of no rank, which
+// have no value in blackjack.
+var ErrInvalidCard = errors.New("analysis: invalid card")
+
+// valid reports whether the card has a value in blackjack.
+func valid(card deck.Card) bool {
+	return card.Suit != deck.Joker && card.Rank >= deck.Ace && card.Rank <= deck.King
+}
+
+// valueIndex returns the index in a Composition of the card's value,
+// which must be valid.
+func valueIndex(card deck.Card) int {
+	if card.Rank == deck.Ace {
+		return ace
+	}
+	return blackjack.Score(card) - 1
+}
+
+// ErrMissingCard is returned when removing a card that isn't in a
+// composition.
+var ErrMissingCard = errors.New("analysis: card not in composition")
+
+// Remove returns the composition without the cards.
+func (c Composition) Remove(cards ...deck.Card) (Composition, error) {
+	for _, card := range cards {
+		if !valid(card) {
+			return c, ErrInvalidCard
+		}
+		v := valueIndex(card)
+		if c[v] == 0 {
+			return c, ErrMissingCard
+		}
+		c[v]--
+	}
+	return c, nil
+}
+
+// Len returns the number of cards in the composition.
+func (c Composition) Len() int {
+	n := 0
+	for _, count := range c {
+		n += count
+	}
+	return n
+}
+
+// DealerOdds are the probabilities of each of the dealer's final
+// outcomes.
+type DealerOdds struct {
+	Totals    [5]float64 // standing on 17 to 21
+	Bust      float64
+	Blackjack float64
+}
+
+// Analysis is the expected value of each move open to a player, per
+// unit of their original bet. Moves the rules don't allow are NaN.
+type Analysis struct {
+	// Dealer are the odds of the dealer's outcomes against the hand as
+	// dealt. If the dealer peeks for blackjack, they are conditional
+	// on the dealer not having one, as are the expected values.
+	Dealer DealerOdds
+
+	Stand  float64
+	Hit    float64 // hitting, then hitting or standing as is best
+	Double float64
+	// SplitEstimate estimates splitting: each split hand is played out
+	// as if it were the only one, drawing from the same composition, and
+	// is never resplit.
+	SplitEstimate float64
+	Surrender     float64
+}
+
+// Best returns the move with the highest expected value, taking
+// SplitEstimate for splitting.
+func (a Analysis) Best() (blackjack.Move, float64) {
+	move, best := blackjack.MoveStand, a.Stand
+	for _, m := range []struct {
+		move blackjack.Move
+		ev   float64
+	}{
+		{blackjack.MoveHit, a.Hit},
+		{blackjack.MoveDouble, a.Double},
+		{blackjack.MoveSplit, a.SplitEstimate},
+		{blackjack.MoveSurrender, a.Surrender},
+	} {
+		if m.ev > best {
+			move, best = m.move, m.ev
+		}
+	}
+	return move, best
+}
+
+// Odds returns the probabilities of the dealer's outcomes with the
+// upcard, drawing from the composition and playing by the rules. The
+// composition must not include the upcard.
+func Odds(comp Composition, upcard deck.Card, opts blackjack.Options) (DealerOdds, error) {
+	if !valid(upcard) {
+		return DealerOdds{}, ErrInvalidCard
+	}
+	a := newAnalyser(opts.WithDefaults(), upcard, false)
+	return a.dealer(dealerHand(a.up), comp).odds(), nil
+}
+
+// Analyse calculates the expected value of each move the rules allow on
+// an unsplit hand against the dealer's upcard, drawing from the
+// composition, which must not include the hand or the upcard.
+//
+// Every move is played out exactly, allowing for the cards removed from
+// the shoe, except splitting, which is only estimated. Analyse returns
+// ErrInvalidCard if the hand or upcard holds a card with no value.
+func Analyse(comp Composition, upcard deck.Card, hand []deck.Card, opts blackjack.Options) (Analysis, error) {
+	if len(hand) < 2 {
+		return Analysis{}, errors.New("analysis: a hand needs at least two cards")
+	}
+	for _, card := range append([]deck.Card{upcard}, hand...) {
+		if !valid(card) {
+			return Analysis{}, ErrInvalidCard
+		}
+	}
+	if blackjack.Score(hand...) > 21 {
+		return Analysis{}, errors.New("analysis: hand is bust")
+	}
+	for _, n := range comp {
+		if n < 0 {
+			return Analysis{}, errors.New("analysis: negative card count")
+		}
+	}
+	if comp.Len() < 2 {
+		return Analysis{}, errors.New("analysis: too few cards left to play")
+	}
+
+	opts = opts.WithDefaults()
+	a := newAnalyser(opts, upcard, true)
+	p := playerHand(hand)
+	nan := math.NaN()
+	result := Analysis{
+		Dealer:        a.dealerOdds(comp).odds(),
+		Stand:         a.stand(p, comp),
+		Hit:           a.hit(p, comp),
+		Double:        nan,
+		SplitEstimate: nan,
+		Surrender:     nan,
+	}
+	if len(hand) == 2 && blackjack.Blackjack(hand...) {
+		result.Stand = a.standBlackjack(comp)
+	}
+	if len(hand) == 2 && opts.DoubleOn.Allows(hand) {
+		result.Double = a.double(p, comp)
+	}
+	if len(hand) == 2 && hand[0].Rank == hand[1].Rank && opts.MaxSplitHands > 1 {
+		result.SplitEstimate = 2 * a.splitHand(valueIndex(hand[0]), comp)
+	}
+	if len(hand) == 2 && (opts.LateSurrender || opts.EarlySurrender) {
+		result.Surrender = -0.5
+	}
+	return result, nil
+}
+
+// hand is a blackjack hand reduced to what matters to its play.
+type hand struct {
+	hard  int  // the total counting aces as 1
+	ace   bool // the hand holds an ace
+	cards int
+}
+
+func playerHand(cards []deck.Card) hand {
+	var h hand
+	for _, c := range cards {
+		h = h.add(valueIndex(c))
+	}
+	return h
+}
+
+func dealerHand(up int) hand {
+	return hand{}.add(up)
+}
+
+// add returns the hand with a card of the given value index added.
+func (h hand) add(v int) hand {
+	h.hard += v + 1
+	h.ace = h.ace || v == ace
+	h.cards++
+	return h
+}
+
+// like returns cards with the hand's total, holding an ace if it does,
+// which blackjack.Score and blackjack.Soft score as they would the hand.
+func (h hand) like() []deck.Card {
+	cards := make([]deck.Card, 0, 4)
+	rest := h.hard
+	if h.ace {
+		cards = append(cards, deck.Card{Rank: deck.Ace})
+		rest--
+	}
+	for rest > 0 {
+		pips := rest
+		switch {
+		case rest == 11:
+			pips = 9 // leaving a two rather than an ace
+		case rest > 10:
+			pips = 10
+		}
+		cards = append(cards, deck.Card{Rank: deck.Rank(pips)})
+		rest -= pips
+	}
+	return cards
+}
+
+func (h hand) score() int {
+	return blackjack.Score(h.like()...)
+}
+
+func (h hand) soft() bool {
+	return blackjack.Soft(h.like()...)
+}
+
+func (h hand) blackjack() bool {
+	return h.cards == 2 && h.score() == 21
+}
+
+// outcome indexes of a dealer distribution.
+const (
+	bust    = 5
+	natural = 6
+)
+
+// dist is the probability of each dealer outcome: standing on 17 to 21
+// at indexes 0 to 4, then bust and blackjack.
+type dist [7]float64
+
+func (d dist) odds() DealerOdds {
+	var o DealerOdds
+	copy(o.Totals[:], d[:bust])
+	o.Bust = d[bust]
+	o.Blackjack = d[natural]
+	return o
+}
+
+// key identifies a hand drawing from a composition.
+type key struct {
+	h    hand
+	comp Composition
+}
+
+// analyser memoises the calculations for one upcard and set of rules.
+type analyser struct {
+	opts blackjack.Options
+	up   int
+	// peek is set if the dealer has checked their hole card for
+	// blackjack, which rules out one value of hole card.
+	peek   bool
+	noPeek int // the value the hole card can't be when peeking
+	final  map[Composition]dist
+	plays  map[key]float64
+}
+
+// newAnalyser returns an analyser for the upcard. If peeked, the dealer
+// has checked for blackjack whenever the rules give them a hole card.
+func newAnalyser(opts blackjack.Options, upcard deck.Card, peeked bool) *analyser {
+	a := &analyser{
+		opts:  opts,
+		up:    valueIndex(upcard),
+		final: make(map[Composition]dist),
+		plays: make(map[key]float64),
+	}
+	if peeked && !opts.NoHoleCard {
+		switch a.up {
+		case ace:
+			a.peek, a.noPeek = true, ten
+		case ten:
+			a.peek, a.noPeek = true, ace
+		}
+	}
+	return a
+}
+
+// dealer returns the distribution of outcomes for the dealer's hand,
+// drawing from the composition.
+func (a *analyser) dealer(h hand, comp Composition) dist {
+	var d dist
+	a.deal(h, &comp, comp.Len(), 1, &d)
+	return d
+}
+
+// deal plays out the dealer's hand, which is reached with probability
+// p, drawing from the n cards of the composition, and adds the
+// probability of each outcome to d. The composition is restored before
+// returning.
+func (a *analyser) deal(h hand, comp *Composition, n int, p float64, d *dist) {
+	score := h.score()
+	switch {
+	case score > 21:
+		d[bust] += p
+	case h.blackjack():
+		d[natural] += p
+	case score > 17 || score == 17 && (a.opts.DealerStandsSoft17 || !h.soft()):
+		d[score-17] += p
+	default:
+		for v, count := range comp {
+			if count == 0 {
+				continue
+			}
+			comp[v]--
+			a.deal(h.add(v), comp, n-1, p*float64(count)/float64(n), d)
+			comp[v]++
+		}
+	}
+}
+
+// holeOdds returns the probability of each value of the dealer's hole
+// card, which has already been dealt, given the cards left and that
+// the dealer doesn't have blackjack.
+func (a *analyser) holeOdds(comp Composition) [values]float64 {
+	var p [values]float64
+	n := float64(comp.Len() - comp[a.noPeek])
+	for v, count := range comp {
+		if v != a.noPeek {
+			p[v] = float64(count) / n
+		}
+	}
+	return p
+}
+
+// dealerOdds returns the distribution of the dealer's outcomes against
+// a player who has stopped drawing from the composition.
+func (a *analyser) dealerOdds(comp Composition) dist {
+	if d, ok := a.final[comp]; ok {
+		return d
+	}
+	var d dist
+	if !a.peek {
+		d = a.dealer(dealerHand(a.up), comp)
+	} else {
+		for v, p := range a.holeOdds(comp) {
+			if p == 0 {
+				continue
+			}
+			comp[v]--
+			sub := a.dealer(dealerHand(a.up).add(v), comp)
+			comp[v]++
+			for i := range d {
+				d[i] += p * sub[i]
+			}
+		}
+	}
+	a.final[comp] = d
+	return d
+}
+
+// drawOdds returns the probability of the player drawing each value
+// from the composition, allowing for the unseen hole card.
+func (a *analyser) drawOdds(comp Composition) [values]float64 {
+	var p [values]float64
+	n := float64(comp.Len())
+	if !a.peek {
+		for v, count := range comp {
+			p[v] = float64(count) / n
+		}
+		return p
+	}
+	for h, ph := range a.holeOdds(comp) {
+		if ph == 0 {
+			continue
+		}
+		for v, count := range comp {
+			if v == h {
+				count--
+			}
+			p[v] += ph * float64(count) / (n - 1)
+		}
+	}
+	return p
+}
+
+// stand returns the expected value of standing on the hand.
+func (a *analyser) stand(h hand, comp Composition) float64 {
+	score := h.score()
+	if score > 21 {
+		return -1
+	}
+	d := a.dealerOdds(comp)
+	ev := d[bust] - d[natural]
+	for i := 0; i < bust; i++ {
+		switch dScore := 17 + i; {
+		case score > dScore:
+			ev += d[i]
+		case score < dScore:
+			ev -= d[i]
+		}
+	}
+	return ev
+}
+
+// standBlackjack returns the expected value of a blackjack, which
+// pushes only against a dealer blackjack.
+func (a *analyser) standBlackjack(comp Composition) float64 {
+	d := a.dealerOdds(comp)
+	return (1 - d[natural]) * a.opts.BlackjackPayout
+}
+
+// hit returns the expected value of hitting the hand, then hitting or
+// standing as is best.
+func (a *analyser) hit(h hand, comp Composition) float64 {
+	ev := 0.0
+	for v, p := range a.drawOdds(comp) {
+		if p == 0 {
+			continue
+		}
+		comp[v]--
+		ev += p * a.play(h.add(v), comp)
+		comp[v]++
+	}
+	return ev
+}
+
+// play returns the expected value of hitting or standing on the hand,
+// whichever is best.
+func (a *analyser) play(h hand, comp Composition) float64 {
+	if h.score() > 21 {
+		return -1
+	}
+	k := key{h, comp}
+	if ev, ok := a.plays[k]; ok {
+		return ev
+	}
+	ev := a.stand(h, comp)
+	if h.score() < 21 {
+		ev = math.Max(ev, a.hit(h, comp))
+	}
+	a.plays[k] = ev
+	return ev
+}
+
+// double returns the expected value of doubling down on the hand.
+func (a *analyser) double(h hand, comp Composition) float64 {
+	ev := 0.0
+	for v, p := range a.drawOdds(comp) {
+		if p == 0 {
+			continue
+		}
+		comp[v]--
+		ev += p * a.stand(h.add(v), comp)
+		comp[v]++
+	}
+	return 2 * ev
+}
+
+// splitHand returns the expected value of one of the hands made by
+// splitting a pair of the given value, played as best the rules allow.
+func (a *analyser) splitHand(v int, comp Composition) float64 {
+	h := hand{}.add(v)
+	ev := 0.0
+	for w, p := range a.drawOdds(comp) {
+		if p == 0 {
+			continue
+		}
+		comp[w]--
+		next := h.add(w)
+		best := a.stand(next, comp)
+		if v != ace || a.opts.HitSplitAces {
+			best = math.Max(best, a.play(next, comp))
+			if !a.opts.NoDoubleAfterSplit && a.opts.DoubleOn.Allows(next.like()) {
+				best = math.Max(best, a.double(next, comp))
+			}
+		}
+		ev += p * best
+		comp[w]++
+	}
+	return ev
+}
@@ -0,0 +1,144 @@
+package analysis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+func cards(t *testing.T, notation ...string) []deck.Card {
+	t.Helper()
+	hand := make([]deck.Card, len(notation))
+	for i, n := range notation {
+		c, err := deck.ParseCard(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hand[i] = c
+	}
+	return hand
+}
+
+func sameMove(a, b blackjack.Move) bool {
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
+
+func remove(t *testing.T, comp Composition, cards ...deck.Card) Composition {
+	t.Helper()
+	comp, err := comp.Remove(cards...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return comp
+}
+
+func TestOdds(t *testing.T) {
+	// Published eight-deck probabilities of the dealer busting.
+	testCases := []struct {
+		upcard string
+		s17    bool
+		want   float64
+	}{
+		{"2S", true, 0.3536},
+		{"6S", true, 0.4232},
+		{"10S", true, 0.2118},
+		{"AS", true, 0.1153},
+	}
+	for _, tc := range testCases {
+		up := cards(t, tc.upcard)[0]
+		odds, err := Odds(remove(t, Shoe(8), up), up, blackjack.Options{DealerStandsSoft17: tc.s17})
+		if err != nil {
+			t.Fatal(err)
+		}
+		total := odds.Bust + odds.Blackjack
+		for _, p := range odds.Totals {
+			total += p
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("%s: probabilities sum to %f", tc.upcard, total)
+		}
+		if math.Abs(odds.Bust-tc.want) > 0.002 {
+			t.Errorf("%s (S17 %t): dealer busts with probability %.4f, want %.4f",
+				tc.upcard, tc.s17, odds.Bust, tc.want)
+		}
+	}
+}
+
+func TestAnalyse(t *testing.T) {
+	testCases := []struct {
+		name   string
+		hand   []string
+		upcard string
+		opts   blackjack.Options
+		want   blackjack.Move
+	}{
+		{"double 11", []string{"6H", "5D"}, "6S", blackjack.Options{}, blackjack.MoveDouble},
+		{"stand on 20", []string{"KH", "QD"}, "6S", blackjack.Options{}, blackjack.MoveStand},
+		{"hit 16", []string{"10H", "6D"}, "10S", blackjack.Options{}, blackjack.MoveHit},
+		{"surrender 16", []string{"10H", "6D"}, "10S", blackjack.Options{LateSurrender: true}, blackjack.MoveSurrender},
+		{"split aces", []string{"AH", "AD"}, "6S", blackjack.Options{}, blackjack.MoveSplit},
+		{"stand on 12 against 4", []string{"10H", "2D"}, "4S", blackjack.Options{}, blackjack.MoveStand},
+		{"hit 12 against 2", []string{"10H", "2D"}, "2S", blackjack.Options{}, blackjack.MoveHit},
+		{"hit without hole card", []string{"6H", "5D"}, "AS", blackjack.Options{NoHoleCard: true}, blackjack.MoveHit},
+	}
+	for _, tc := range testCases {
+		hand, up := cards(t, tc.hand...), cards(t, tc.upcard)[0]
+		comp := remove(t, Shoe(6), append(hand, up)...)
+		a, err := Analyse(comp, up, hand, tc.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if move, _ := a.Best(); !sameMove(move, tc.want) {
+			t.Errorf("%s: best move isn't the expected one: %+v", tc.name, a)
+		}
+	}
+}
+
+func TestAnalyseStand(t *testing.T) {
+	// Standing on 16 only wins if the dealer busts, and the dealer never
+	// has blackjack after peeking.
+	hand, up := cards(t, "10H", "6D"), cards(t, "10S")[0]
+	a, err := Analyse(remove(t, Shoe(6), append(hand, up)...), up, hand, blackjack.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Dealer.Blackjack != 0 {
+		t.Errorf("dealer has blackjack with probability %f after peeking", a.Dealer.Blackjack)
+	}
+	if want := 2*a.Dealer.Bust - 1; math.Abs(a.Stand-want) > 1e-9 {
+		t.Errorf("standing on 16 is worth %f, want %f", a.Stand, want)
+	}
+	if !math.IsNaN(a.Surrender) {
+		t.Errorf("surrender is worth %f without late surrender, want NaN", a.Surrender)
+	}
+}
+
+func TestAnalyseErrors(t *testing.T) {
+	up := cards(t, "10S")[0]
+	if _, err := Analyse(Shoe(1), up, cards(t, "10H"), blackjack.Options{}); err == nil {
+		t.Error("expected an error analysing a one-card hand")
+	}
+	if _, err := Analyse(Shoe(1), up, cards(t, "10H", "6D", "9C"), blackjack.Options{}); err == nil {
+		t.Error("expected an error analysing a bust hand")
+	}
+	if _, err := (Composition{}).Remove(up); err != ErrMissingCard {
+		t.Errorf("removing a missing card: got %v, want %v", err, ErrMissingCard)
+	}
+
+	joker := deck.Card{Suit: deck.Joker}
+	if _, err := Analyse(Shoe(1), joker, cards(t, "10H", "6D"), blackjack.Options{}); err != ErrInvalidCard {
+		t.Errorf("analysing against a Joker: got %v, want %v", err, ErrInvalidCard)
+	}
+	if _, err := Analyse(Shoe(1), up, []deck.Card{{Rank: deck.Ten, Suit: deck.Hearts}, joker}, blackjack.Options{}); err != ErrInvalidCard {
+		t.Errorf("analysing a hand with a Joker: got %v, want %v", err, ErrInvalidCard)
+	}
+	if _, err := Odds(Shoe(1), joker, blackjack.Options{}); err != ErrInvalidCard {
+		t.Errorf("dealer odds with a Joker: got %v, want %v", err, ErrInvalidCard)
+	}
+	if _, err := Shoe(1).Remove(joker); err != ErrInvalidCard {
+		t.Errorf("removing a Joker: got %v, want %v", err, ErrInvalidCard)
+	}
+}
@@ -0,0 +1,79 @@
+// Package bankroll sizes blackjack bets to a bankroll by the Kelly
+// criterion, which bets the fraction of the bankroll that grows it
+// fastest in the long run.
+package bankroll
+
+import "math"
+
+// Variance is the variance of the result of a round of blackjack, in
+// squared opening bets, under common rules. Doubles and splits push it
+// above 1.
+const Variance = 1.3
+
+// BaseEdge is the edge off the top of the shoe under common rules, as
+// a fraction of the bet, before any cards have been counted.
+const BaseEdge = -0.005
+
+// EdgePerCount is the edge each point of the Hi-Lo true count adds, as
+// a fraction of the bet.
+const EdgePerCount = 0.005
+
+// Kelly returns the fraction of the bankroll to bet on a game with the
+// given edge, as a fraction of the bet, and variance: edge / variance,
+// or 0 if the game has no edge.
+func Kelly(edge, variance float64) float64 {
+	if edge <= 0 || variance <= 0 {
+		return 0
+	}
+	return edge / variance
+}
+
+// CountEdge estimates the edge at a Hi-Lo true count, from the edge off
+// the top of the shoe.
+func CountEdge(base, trueCount float64) float64 {
+	return base + EdgePerCount*trueCount
+}
+
+// A Sizer sizes bets as a fraction of the Kelly bet within the table
+// limits.
+type Sizer struct {
+	Fraction float64 // the fraction of the Kelly bet to make, e.g. 0.5 for half Kelly; defaults to 1
+	Variance float64 // defaults to Variance
+	Unit     int     // bets are whole numbers of Units; defaults to 1
+	MinBet   int
+	MaxBet   int // 0 for no maximum
+}
+
+// Bet returns the bet on a game with the given edge for the bankroll,
+// rounded down to whole units and kept within the table limits. It bets
+// the table minimum when the game has no edge, and never more than the
+// bankroll. It returns 0 if the bankroll can't cover the minimum, since
+// the table wouldn't take the bet.
+func (s Sizer) Bet(bankroll int, edge float64) int {
+	if bankroll < s.MinBet {
+		return 0
+	}
+	fraction, variance, unit := s.Fraction, s.Variance, s.Unit
+	if fraction == 0 {
+		fraction = 1
+	}
+	if variance == 0 {
+		variance = Variance
+	}
+	if unit == 0 {
+		unit = 1
+	}
+
+	kelly := fraction * Kelly(edge, variance) * float64(bankroll)
+	bet := int(math.Floor(kelly/float64(unit))) * unit
+	if bet < s.MinBet {
+		bet = s.MinBet
+	}
+	if s.MaxBet > 0 && bet > s.MaxBet {
+		bet = s.MaxBet
+	}
+	if bet > bankroll {
+		bet = bankroll
+	}
+	return bet
+}
@@ -0,0 +1,48 @@
+package bankroll
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKelly(t *testing.T) {
+	testCases := []struct {
+		edge, variance, want float64
+	}{
+		{0.01, 1.3, 0.01 / 1.3},
+		{0.02, 1, 0.02},
+		{0, 1.3, 0},
+		{-0.005, 1.3, 0},
+	}
+	for _, tc := range testCases {
+		if got := Kelly(tc.edge, tc.variance); math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("Kelly(%g, %g) = %g, want %g", tc.edge, tc.variance, got, tc.want)
+		}
+	}
+	if got, want := CountEdge(-0.005, 3), 0.01; math.Abs(got-want) > 1e-12 {
+		t.Errorf("edge at +3 is %g, want %g", got, want)
+	}
+}
+
+func TestSizerBet(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sizer    Sizer
+		bankroll int
+		edge     float64
+		want     int
+	}{
+		{"full Kelly", Sizer{Variance: 1}, 10000, 0.02, 200},
+		{"half Kelly", Sizer{Fraction: 0.5, Variance: 1}, 10000, 0.02, 100},
+		{"whole units", Sizer{Variance: 1, Unit: 25}, 10000, 0.013, 125},
+		{"no edge", Sizer{MinBet: 10}, 10000, -0.005, 10},
+		{"table maximum", Sizer{Variance: 1, MaxBet: 500}, 100000, 0.02, 500},
+		{"bankroll", Sizer{Variance: 0.001, MinBet: 10}, 60, 0.01, 60},
+		{"below minimum", Sizer{MinBet: 100}, 60, 0.01, 0},
+	}
+	for _, tc := range testCases {
+		if got := tc.sizer.Bet(tc.bankroll, tc.edge); got != tc.want {
+			t.Errorf("%s: bet %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package blackjack
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/angusgmorrison/gophercises/deck"
 )
@@ -13,8 +14,134 @@ type AI interface {
 	Outcome(hand [][]deck.Card, dealer []deck.Card)
 }
 
-// dealerAI is the default implentation of the blackjack dealer.
-type dealerAI struct{}
+// InsuranceAI is implemented by AIs that want to be offered insurance
+// when the dealer shows an ace. Insurance returns true to take it, or,
+// if hand is a blackjack, to take even money.
+type InsuranceAI interface {
+	AI
+	Insurance(hand []deck.Card, dealer deck.Card) bool
+}
+
+// EarlySurrenderAI is implemented by AIs that want to be offered early
+// surrender, before the dealer checks for blackjack. EarlySurrender
+// returns true to give up the hand for half the bet.
+type EarlySurrenderAI interface {
+	AI
+	EarlySurrender(hand []deck.Card, dealer deck.Card) bool
+}
+
+// ResultAI is implemented by AIs that want to know how their bets were
+// settled at the end of each round.
+type ResultAI interface {
+	AI
+	Result(r Result)
+}
+
+// WatcherAI is implemented by AIs that want to see every card turned
+// face up at the table, at any seat, as it is revealed. The dealer's
+// hole card is revealed when the round is settled. Bet is told when
+// the shoe is reshuffled.
+type WatcherAI interface {
+	AI
+	Seen(card deck.Card)
+}
+
+// RetryAI is implemented by AIs that want to be told why a bet or move
+// was rejected. AIs that don't implement it are simply asked again. An
+// AI that is rejected too many times in a row has the table minimum bet
+// or the hand stood on for it.
+type RetryAI interface {
+	AI
+	Rejected(err error)
+}
+
+// SessionAI is implemented by AIs that bring their own session rules to
+// the table. Any rule left zero is taken from the game's Options.
+type SessionAI interface {
+	AI
+	Session() Session
+}
+
+// SideBetAI is implemented by AIs that place side bets at tables that
+// offer them. SideBets is called after each Bet with the side bets the
+// table offers, and returns the amount to wager on each, in the same
+// order. A wager of 0 declines a side bet, as does leaving it off the
+// end.
+type SideBetAI interface {
+	AI
+	SideBets(offered []SideBet) []int
+}
+
+// Wrapper is embedded by AIs that wrap another AI to watch or change
+// how it plays. It passes on the optional methods the wrapped AI
+// implements, declining insurance, early surrender and side bets and
+// bringing no session rules for one that doesn't, so that the wrapper
+// need only define the methods it changes.
+type Wrapper struct {
+	AI
+}
+
+// Insurance passes the decision to the wrapped AI if it takes insurance.
+func (w Wrapper) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	if iai, ok := w.AI.(InsuranceAI); ok {
+		return iai.Insurance(hand, dealer)
+	}
+	return false
+}
+
+// EarlySurrender passes the decision to the wrapped AI if it surrenders.
+func (w Wrapper) EarlySurrender(hand []deck.Card, dealer deck.Card) bool {
+	if sai, ok := w.AI.(EarlySurrenderAI); ok {
+		return sai.EarlySurrender(hand, dealer)
+	}
+	return false
+}
+
+// Result tells the wrapped AI how its bets were settled if it wants to
+// know.
+func (w Wrapper) Result(r Result) {
+	if rai, ok := w.AI.(ResultAI); ok {
+		rai.Result(r)
+	}
+}
+
+// Seen shows the card to the wrapped AI if it watches the table.
+func (w Wrapper) Seen(card deck.Card) {
+	if wai, ok := w.AI.(WatcherAI); ok {
+		wai.Seen(card)
+	}
+}
+
+// Rejected tells the wrapped AI why its bet or move was rejected if it
+// wants to know.
+func (w Wrapper) Rejected(err error) {
+	if rai, ok := w.AI.(RetryAI); ok {
+		rai.Rejected(err)
+	}
+}
+
+// Session returns the wrapped AI's session rules if it sets any.
+func (w Wrapper) Session() Session {
+	if sai, ok := w.AI.(SessionAI); ok {
+		return sai.Session()
+	}
+	return Session{}
+}
+
+// SideBets places the wrapped AI's side bets if it places any.
+func (w Wrapper) SideBets(offered []SideBet) []int {
+	if sai, ok := w.AI.(SideBetAI); ok {
+		return sai.SideBets(offered)
+	}
+	return nil
+}
+
+// dealerAI is the default implentation of the blackjack dealer. It
+// hits 16 or less and stands on 17 or more, hitting soft 17 if
+// hitSoft17 is set.
+type dealerAI struct {
+	hitSoft17 bool
+}
 
 func (ai dealerAI) Bet(shuffled bool) int {
 	// noop
@@ -23,7 +150,7 @@ func (ai dealerAI) Bet(shuffled bool) int {
 
 func (ai dealerAI) Play(hand []deck.Card, dealer deck.Card) Move {
 	score := Score(hand...)
-	if score <= 16 || score == 17 && Soft(hand...) {
+	if score <= 16 || ai.hitSoft17 && score == 17 && Soft(hand...) {
 		return MoveHit
 	}
 	return MoveStand
@@ -34,18 +161,24 @@ func (ai dealerAI) Outcome(hand [][]deck.Card, dealer []deck.Card) {
 }
 
 // HumanAI conceals the implementation of a default human player
-// for a blackjack game.
-func HumanAI() AI {
-	return humanAI{}
+// for a blackjack game with the given rules.
+func HumanAI(opts Options) AI {
+	return humanAI{minBet: opts.WithDefaults().MinBet}
 }
 
-type humanAI struct{}
+type humanAI struct {
+	minBet int
+}
+
+// renderer draws the table for humanAI, in colour unless the NO_COLOR
+// environment variable is set.
+var renderer = deck.Renderer{Colour: os.Getenv("NO_COLOR") == ""}
 
 func (ai humanAI) Bet(shuffled bool) int {
 	if shuffled {
 		fmt.Println("Deck was just shuffled...")
 	}
-	fmt.Println("What would you like to bet? (min. 100)")
+	fmt.Printf("What would you like to bet? (min. %d)\n", ai.minBet)
 	var bet int
 	fmt.Scanf("%d\n", &bet)
 	return bet
@@ -53,17 +186,20 @@ func (ai humanAI) Bet(shuffled bool) int {
 
 // Accepted player inputs.
 const (
-	double = "d"
-	hit    = "h"
-	stand  = "s"
+	double    = "d"
+	hit       = "h"
+	split     = "p"
+	stand     = "s"
+	surrender = "r"
+	yes       = "y"
 )
 
 func (ai humanAI) Play(hand []deck.Card, dealer deck.Card) Move {
 	for {
 		var input string
-		fmt.Println("AI:", hand)
-		fmt.Println("Dealer:", dealer)
-		fmt.Println("What will you do? (h)it, (s)tand, (d)ouble")
+		fmt.Printf("AI:\n%s\n", renderer.Art(hand...))
+		fmt.Printf("Dealer:\n%s\n", renderer.HiddenArt([]deck.Card{dealer}, 1))
+		fmt.Println("What will you do? (h)it, (s)tand, (d)ouble, s(p)lit, su(r)render")
 		fmt.Scanf("%s\n", &input)
 
 		switch input {
@@ -71,18 +207,53 @@ func (ai humanAI) Play(hand []deck.Card, dealer deck.Card) Move {
 			return MoveDouble
 		case hit:
 			return MoveHit
+		case split:
+			return MoveSplit
+		case surrender:
+			return MoveSurrender
 		case stand:
 			return MoveStand
 		default:
-			fmt.Println("Command not recognised: enter (h)it, (s)tand or (d)ouble")
+			fmt.Println("Command not recognised: enter (h)it, (s)tand, (d)ouble, s(p)lit or su(r)render")
 		}
 	}
 }
 
+func (ai humanAI) Rejected(err error) {
+	fmt.Println(err)
+}
+
+func (ai humanAI) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	fmt.Printf("AI:\n%s\n", renderer.Art(hand...))
+	fmt.Printf("Dealer:\n%s\n", renderer.HiddenArt([]deck.Card{dealer}, 1))
+	if Blackjack(hand...) {
+		fmt.Println("Take even money? (y)es, (n)o")
+	} else {
+		fmt.Println("Take insurance? (y)es, (n)o")
+	}
+	return readYes()
+}
+
+func (ai humanAI) EarlySurrender(hand []deck.Card, dealer deck.Card) bool {
+	fmt.Printf("AI:\n%s\n", renderer.Art(hand...))
+	fmt.Printf("Dealer:\n%s\n", renderer.HiddenArt([]deck.Card{dealer}, 1))
+	fmt.Println("Surrender before the dealer checks for blackjack? (y)es, (n)o")
+	return readYes()
+}
+
+// readYes reads a line of input, returning true if it's a yes.
+func readYes() bool {
+	var input string
+	fmt.Scanf("%s\n", &input)
+	return input == yes
+}
+
 func (ai humanAI) Outcome(hand [][]deck.Card, dealer []deck.Card) {
 	fmt.Println("==FINAL HANDS==")
-	fmt.Println("AI:", hand)
-	fmt.Println("Dealer:", dealer)
+	for _, h := range hand {
+		fmt.Printf("AI:\n%s\n", renderer.Art(h...))
+	}
+	fmt.Printf("Dealer:\n%s\n", renderer.Art(dealer...))
 	fmt.Println()
 	// fmt.Printf("AI: %s\nScore: %d\n", ret.AI, pScore)
 	// fmt.Printf("AI: %s\nScore: %d\n", ret.Dealer, dScore)
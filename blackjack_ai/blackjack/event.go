@@ -0,0 +1,70 @@
+//go:generate stringer -type=EventKind
+
+package blackjack
+
+import "github.com/angusgmorrison/gophercises/deck"
+
+// EventKind is the kind of thing that happened at the table.
+type EventKind uint8
+
+const (
+	HandStarted EventKind = iota // a seat placed its bet for the round
+	CardDealt                    // a card was turned face up
+	MoveMade                     // a player or the dealer made a move
+	HandSettled                  // a seat's bets were settled
+	Reshuffled                   // the shoe was reshuffled
+)
+
+// DealerSeat is the Seat of events at the dealer's hand or the shoe.
+const DealerSeat = -1
+
+// Event is a step in the progress of a game, as told to the game's
+// Observers. Only the fields the kind uses are set.
+type Event struct {
+	Kind  EventKind
+	Round int // the round, counted from 1
+	Seat  int // the seat, counted from 0, or DealerSeat for the dealer and the shoe
+	Hand  int // the index of the seat's hand, which is above 0 only after splitting
+
+	Bet    int       // HandStarted: the amount bet
+	Card   deck.Card // CardDealt: the card turned face up
+	Move   Move      // MoveMade: the move, made after any cards it drew are dealt
+	Result *Result   // HandSettled: the settlement of the seat's bets
+}
+
+// Observer is implemented by anything that wants to follow the progress
+// of a game, such as a UI, logger or card counter, without playing a
+// seat.
+//
+// Cards are dealt as they are turned face up, so the dealer's hole card
+// is dealt when the round is settled. Reshuffled is sent whenever the
+// shoe is shuffled, including when it runs out mid-round and is
+// refilled from the discards.
+type Observer interface {
+	Observe(e Event)
+}
+
+// ObserverFunc adapts a function to an Observer.
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+// Observe adds an observer to be told of every event in the game, in
+// the order the observers were added.
+func (g *Game) Observe(o Observer) {
+	g.observers = append(g.observers, o)
+}
+
+// emit tells the game's observers of the event, stamping it with the
+// current round.
+func emit(g *Game, e Event) {
+	if len(g.observers) == 0 {
+		return
+	}
+	e.Round = g.round
+	for _, o := range g.observers {
+		o.Observe(e)
+	}
+}
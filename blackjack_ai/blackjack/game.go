@@ -1,8 +1,12 @@
 package blackjack
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
 
 	"github.com/angusgmorrison/gophercises/deck"
 )
@@ -11,25 +15,173 @@ import (
 type Options struct {
 	NDecks             int
 	NHands             int
-	BlackjackPayout    float64
-	ReshuffleThreshold int // the fraction of the deck below which to reshuffle (3 == 1/3)
+	BlackjackPayout    float64 // the multiple of the bet paid on a blackjack, e.g. Payout3to2
+	ReshuffleThreshold int     // the fraction of the deck below which to reshuffle (3 == 1/3)
+	Seed               *int64  // seeds every shuffle in the game; nil picks a seed from the clock
+	// Shuffle returns the Option used to shuffle the shoe, drawing its
+	// randomness from src. Each shuffle is given the cards in the order
+	// the last shoe left them, so that a physical shuffle can leave some
+	// of that order behind. Defaults to a perfect Fisher-Yates shuffle;
+	// use deck.WithShuffler to model a dealer's physical shuffles, e.g.
+	//
+	//	Shuffle: deck.WithShuffler(func(s *deck.Shuffler) deck.Option { return s.Riffle(7) })
+	Shuffle func(src rand.Source) deck.Option
+
+	MaxSplitHands      int  // the most hands a player may split into
+	HitSplitAces       bool // allow hitting split aces, which otherwise receive one card each
+	NoDoubleAfterSplit bool // forbid doubling down on hands made by splitting
+
+	Insurance      bool // offer insurance, or even money on a blackjack, against a dealer's ace
+	LateSurrender  bool // allow surrendering the first two cards after the dealer checks for blackjack
+	EarlySurrender bool // also allow surrendering before the dealer checks for blackjack
+
+	DealerStandsSoft17 bool       // the dealer stands on soft 17 (S17) rather than hitting it (H17)
+	DoubleOn           DoubleRule // the two-card totals that may be doubled down on
+	ResplitAces        bool       // allow splitting a pair of aces made by splitting aces
+	NoHoleCard         bool       // the dealer takes a second card only after the players finish (ENHC)
+	MinBet             int
+	MaxBet             int // 0 for no maximum
+	// Bankroll, StopLoss and WinGoal are the session rules for every
+	// seat, unless its AI sets its own as a SessionAI.
+	Bankroll int
+	StopLoss int
+	WinGoal  int
+	// SideBets are the side bets offered at the table, which seats
+	// whose AIs are SideBetAIs may place alongside their bets.
+	SideBets []SideBet
+}
+
+// Session holds the rules a seat plays a session by.
+type Session struct {
+	// Bankroll is the number of chips the seat starts with, which caps
+	// its bets. A seat that can't cover the minimum bet goes bust and
+	// leaves the table. 0 gives the seat unlimited chips.
+	Bankroll int
+	StopLoss int // leave the table after losing this many chips; 0 for no limit
+	WinGoal  int // leave the table after winning this many chips; 0 for no goal
+}
+
+// With returns the session rules overridden by the non-zero rules in
+// own.
+func (s Session) With(own Session) Session {
+	if own.Bankroll != 0 {
+		s.Bankroll = own.Bankroll
+	}
+	if own.StopLoss != 0 {
+		s.StopLoss = own.StopLoss
+	}
+	if own.WinGoal != 0 {
+		s.WinGoal = own.WinGoal
+	}
+	return s
+}
+
+// SessionEnd is the reason a seat's session ended.
+type SessionEnd uint8
+
+const (
+	InSession   SessionEnd = iota // the session hasn't ended
+	Bust                          // the seat can't cover the minimum bet
+	StoppedLoss                   // the seat lost its stop-loss
+	ReachedGoal                   // the seat won its win goal
+)
+
+// End reports whether a session with the given balance has ended at a
+// table with the given minimum bet, and why.
+func (s Session) End(balance, minBet int) SessionEnd {
+	switch {
+	case s.Bankroll > 0 && s.Bankroll+balance < minBet:
+		return Bust
+	case s.StopLoss > 0 && -balance >= s.StopLoss:
+		return StoppedLoss
+	case s.WinGoal > 0 && balance >= s.WinGoal:
+		return ReachedGoal
+	}
+	return InSession
+}
+
+// Common blackjack payouts for Options.BlackjackPayout.
+const (
+	Payout3to2 = 1.5
+	Payout6to5 = 1.2
+)
+
+// DoubleRule restricts the hands a player may double down on.
+type DoubleRule uint8
+
+const (
+	DoubleAny    DoubleRule = iota // double on any first two cards
+	Double9To11                    // double only on totals of 9, 10 or 11
+	Double10To11                   // double only on totals of 10 or 11
+)
+
+// Allows reports whether the rule allows doubling on a two-card hand.
+func (r DoubleRule) Allows(hand []deck.Card) bool {
+	score := Score(hand...)
+	switch r {
+	case Double9To11:
+		return score >= 9 && score <= 11
+	case Double10To11:
+		return score >= 10 && score <= 11
+	default:
+		return true
+	}
 }
 
 // Option defaults
 const (
 	defaultNDecks             = 3
 	defaultNHands             = 100
-	defaultBlackjackPayout    = 1.5
+	defaultBlackjackPayout    = Payout3to2
 	defaultReshuffleThreshold = 3
+	defaultMaxSplitHands      = 4
+	defaultMinBet             = 100
 )
 
 // New starts a new game with the specified options.
 func New(opts Options) Game {
+	opts = opts.WithDefaults()
 	g := Game{
 		phase:    playerTurn,
-		dealerAI: dealerAI{},
+		dealerAI: dealerAI{hitSoft17: !opts.DealerStandsSoft17},
+	}
+
+	seed := time.Now().UnixNano()
+	if opts.Seed != nil {
+		seed = *opts.Seed
 	}
 
+	g.nDecks = opts.NDecks
+	g.nHands = opts.NHands
+	g.blackjackPayout = opts.BlackjackPayout
+	g.minCards = (52 * g.nDecks) / opts.ReshuffleThreshold
+	g.seed = seed
+	g.shuffle = opts.Shuffle(rand.NewSource(seed))
+	g.maxSplitHands = opts.MaxSplitHands
+	g.hitSplitAces = opts.HitSplitAces
+	g.doubleAfterSplit = !opts.NoDoubleAfterSplit
+	g.insurance = opts.Insurance
+	g.lateSurrender = opts.LateSurrender || opts.EarlySurrender
+	g.earlySurrender = opts.EarlySurrender
+	g.doubleOn = opts.DoubleOn
+	g.resplitAces = opts.ResplitAces
+	g.holeCard = !opts.NoHoleCard
+	g.minBet = opts.MinBet
+	g.maxBet = opts.MaxBet
+	g.sideBets = opts.SideBets
+	g.session = Session{
+		Bankroll: opts.Bankroll,
+		StopLoss: opts.StopLoss,
+		WinGoal:  opts.WinGoal,
+	}
+
+	return g
+}
+
+// WithDefaults returns the options with defaults filled in for any left
+// unset, as New plays them. Seed is left unset, since its default comes
+// from the clock.
+func (opts Options) WithDefaults() Options {
 	if opts.NDecks == 0 {
 		opts.NDecks = defaultNDecks
 	}
@@ -42,13 +194,27 @@ func New(opts Options) Game {
 	if opts.ReshuffleThreshold == 0 {
 		opts.ReshuffleThreshold = defaultReshuffleThreshold
 	}
+	if opts.Shuffle == nil {
+		opts.Shuffle = deck.ShuffleWith
+	}
+	if opts.MaxSplitHands == 0 {
+		opts.MaxSplitHands = defaultMaxSplitHands
+	}
+	if opts.MinBet == 0 {
+		opts.MinBet = defaultMinBet
+	}
+	return opts
+}
 
-	g.nDecks = opts.NDecks
-	g.nHands = opts.NHands
-	g.blackjackPayout = opts.BlackjackPayout
-	g.minCards = (52 * g.nDecks) / opts.ReshuffleThreshold
+// Seed returns the seed used to shuffle the game's decks. Passing it
+// as Options.Seed to New replays the game with exactly the same cards.
+func (g *Game) Seed() int64 {
+	return g.seed
+}
 
-	return g
+// Seed returns a pointer to seed, for setting Options.Seed.
+func Seed(seed int64) *int64 {
+	return &seed
 }
 
 // Game holds the current, mutable state of the game.
@@ -58,15 +224,65 @@ type Game struct {
 	minCards        int
 	blackjackPayout float64
 
-	phase phase
-	deck  []deck.Card
+	maxSplitHands    int
+	hitSplitAces     bool
+	doubleAfterSplit bool
+	insurance        bool
+	lateSurrender    bool
+	earlySurrender   bool
+	doubleOn         DoubleRule
+	resplitAces      bool
+	holeCard         bool
+	minBet           int
+	maxBet           int
+	session          Session // the default session rules for each seat
+	sideBets         []SideBet
+
+	seed    int64
+	shuffle deck.Option
 
-	player    []deck.Card
-	playerBet int
-	balance   int
+	phase    phase
+	shoe     *deck.Shoe
+	refilled bool // the shoe ran out and was refilled from the discards
+
+	seats   []*seat
+	seatIdx int // index in seats of the seat being played
+	handIdx int // index in the current seat's hands of the hand being played
 
 	dealer   []deck.Card
 	dealerAI AI
+
+	round     int // the round being played, counted from 1
+	observers []Observer
+}
+
+// MaxSeats is the number of seats at a blackjack table.
+const MaxSeats = 7
+
+// seat is a place at the table, played by an AI with its own bankroll.
+type seat struct {
+	ai      AI
+	index   int // the seat's place at the table, counted from 0
+	session Session
+	hands   []hand
+	bet     int
+	insured int // the seat's insurance bet
+	balance int
+
+	sideBets []int       // the seat's wager on each of the table's side bets
+	dealt    []deck.Card // the seat's first two cards, on which side bets are settled
+}
+
+// hand is one of the player's hands and the bet riding on it. A
+// player holds more than one hand after splitting.
+type hand struct {
+	cards     []deck.Card
+	bet       int
+	split     bool // the hand was made by splitting a pair
+	splitAces bool // the hand was made by splitting aces
+
+	surrendered bool // the player gave up half the bet to end the hand
+	evenMoney   bool // the player took even money on a blackjack
 }
 
 // Phase represents the current stage of gameplay.
@@ -78,74 +294,425 @@ const (
 	handOver
 )
 
+// maxAttempts is the number of times an AI is asked for a bet or move
+// before the game makes one for it.
+const maxAttempts = 3
+
 // Play begins the game, taking in a player AI, the number of decks
 // to play with and the number of rounds to play, and returning the
 // player's final balance.
-func (g *Game) Play(player AI) int {
+func (g *Game) Play(player AI) (int, error) {
+	return g.PlayContext(context.Background(), player)
+}
+
+// PlayContext plays the game like Play, stopping early if the context
+// is cancelled.
+func (g *Game) PlayContext(ctx context.Context, player AI) (int, error) {
+	balances, err := g.PlayTableContext(ctx, player)
+	if balances == nil {
+		return 0, err
+	}
+	return balances[0], err
+}
+
+// PlayTable plays the game with each AI in its own seat at the table,
+// dealt from a shared shoe in seat order, returning the final balance
+// of each seat. At most MaxSeats players may sit at the table.
+//
+// An AI that makes a bet or move the rules don't allow is asked again,
+// up to maxAttempts times, after which its seat bets the table minimum
+// or stands on the hand, so that one AI can't hold up the rest of the
+// table. If the game can't continue, it returns the balances so far with
+// the error.
+func (g *Game) PlayTable(players ...AI) ([]int, error) {
+	return g.PlayTableContext(context.Background(), players...)
+}
+
+// PlayTableContext plays the game like PlayTable, stopping early if the
+// context is cancelled. The round in play when it is cancelled is
+// finished, so that every bet is settled, before it returns the
+// balances with the context's error.
+func (g *Game) PlayTableContext(ctx context.Context, players ...AI) ([]int, error) {
+	if len(players) == 0 || len(players) > MaxSeats {
+		return nil, fmt.Errorf("blackjack: a table seats 1 to %d players", MaxSeats)
+	}
+	seats := make([]*seat, len(players))
+	for i, ai := range players {
+		seats[i] = &seat{ai: ai, index: i, session: session(g, ai)}
+	}
+
+	err := playRounds(ctx, g, seats)
+	balances := make([]int, len(seats))
+	for i, s := range seats {
+		balances[i] = s.balance
+	}
+	return balances, err
+}
+
+// playRounds plays the game's rounds with the seats, ending early if
+// every seat leaves the table or the context is cancelled.
+func playRounds(ctx context.Context, g *Game, seats []*seat) error {
+	g.seats = seats
 	for i := 0; i < g.nHands; i++ {
-		shuffled := false
-		if len(g.deck) < g.minCards {
-			g.deck = deck.New(deck.Deck(g.nDecks), deck.Shuffle)
-			shuffled = true
+		g.seats = seatsInPlay(g)
+		if len(g.seats) == 0 {
+			return nil
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		g.round++
+		if err := playRound(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		bet(g, player, shuffled)
-		shuffled = false
+// session returns the session rules for the AI's seat: the table's,
+// overridden by any the AI sets.
+func session(g *Game, ai AI) Session {
+	if sai, ok := ai.(SessionAI); ok {
+		return g.session.With(sai.Session())
+	}
+	return g.session
+}
 
-		deal(g)
-		if Blackjack(g.dealer...) {
-			endHand(g, player)
-			continue
+// seatsInPlay returns the seats whose sessions haven't ended.
+func seatsInPlay(g *Game) []*seat {
+	var seats []*seat
+	for _, s := range g.seats {
+		if s.session.End(s.balance, g.minBet) == InSession {
+			seats = append(seats, s)
 		}
+	}
+	return seats
+}
+
+// playRound plays a single round at the table, from bets to settlement.
+func playRound(g *Game) error {
+	shuffled := false
+	if g.shoe == nil || g.shoe.CutCardReached() || g.refilled {
+		reshuffle(g)
+		shuffled = true
+	}
 
-		for g.phase == playerTurn {
-			hand := make([]deck.Card, len(g.player))
-			copy(hand, g.player)
-			move := player.Play(hand, g.dealer[0])
-			if err := move(g); err != nil {
-				switch err {
-				case errBust:
-					MoveStand(g)
-				default:
-					panic(err)
-				}
+	for _, s := range g.seats {
+		bet(g, s, shuffled)
+		emit(g, Event{Kind: HandStarted, Seat: s.index, Bet: s.bet})
+	}
+
+	if err := deal(g); err != nil {
+		return err
+	}
+	for _, s := range g.seats {
+		offerEarlySurrender(g, s)
+		offerInsurance(g, s)
+	}
+	if Blackjack(g.dealer...) {
+		endHand(g)
+		return nil
+	}
+
+	if err := startHand(g); err != nil {
+		return err
+	}
+	for g.phase == playerTurn {
+		if err := playTurn(g); err != nil {
+			return err
+		}
+	}
+
+	if !liveHands(g) {
+		g.phase = handOver
+	}
+	for g.phase == dealerTurn {
+		hand := copyCards(g.dealer)
+		move := g.dealerAI.Play(hand, hand[0])
+		if err := move(g); err != nil && err != errBust {
+			return err
+		}
+		emit(g, Event{Kind: MoveMade, Seat: DealerSeat, Move: move})
+	}
+
+	endHand(g)
+	return nil
+}
+
+// playTurn asks the AI of the seat being played for its move on the
+// current hand and makes it, asking again if the rules don't allow it.
+// After maxAttempts invalid moves, the hand is stood on.
+func playTurn(g *Game) error {
+	seatIdx, handIdx := g.seatIdx, g.handIdx
+	s := g.seats[seatIdx]
+	for attempt := 1; ; attempt++ {
+		move := s.ai.Play(copyCards(s.hands[handIdx].cards), g.dealer[0])
+		var err error
+		if move == nil {
+			err = fmt.Errorf("%w: no move made", ErrInvalidMove)
+		} else {
+			err = move(g)
+		}
+
+		made := Event{Kind: MoveMade, Seat: s.index, Hand: handIdx, Move: move}
+		switch {
+		case err == nil:
+			emit(g, made)
+			return nil
+		case err == errBust:
+			emit(g, made)
+			return MoveStand(g)
+		case errors.Is(err, ErrInvalidMove):
+			reject(s, err)
+			if attempt == maxAttempts {
+				made.Move = MoveStand
+				emit(g, made)
+				return MoveStand(g)
 			}
+		default:
+			return err
 		}
+	}
+}
+
+// reject tells the seat's AI why its bet or move was rejected, if it
+// wants to know.
+func reject(s *seat, err error) {
+	if rai, ok := s.ai.(RetryAI); ok {
+		rai.Rejected(err)
+	}
+}
+
+// liveHands reports whether any player hands remain for the dealer to
+// play against, rather than all having bust or been settled early.
+func liveHands(g *Game) bool {
+	for _, s := range g.seats {
+		for _, h := range s.hands {
+			if !h.surrendered && !h.evenMoney && Score(h.cards...) <= 21 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reshuffle gathers up the shoe, as a dealer would the discards and
+// the cards left undealt, shuffles it and places the cut card minCards
+// from the back. The first shoe is shuffled from new decks.
+func reshuffle(g *Game) {
+	if g.shoe == nil {
+		g.shoe = deck.NewShoe(deck.New(deck.Deck(g.nDecks), g.shuffle))
+	} else {
+		g.shoe = deck.NewShoe(g.shuffle(g.shoe.Collect()))
+	}
+	g.shoe.PlaceCutCard(g.shoe.Len() - g.minCards)
+	g.refilled = false
+	emit(g, Event{Kind: Reshuffled, Seat: DealerSeat})
+}
+
+// bet asks the seat's AI for its bet, and any side bets, asking again if
+// they're outside the table limits or more than the seat's chips. After
+// maxAttempts invalid bets, the seat bets the table minimum, which it
+// can always cover while in session, without side bets.
+func bet(g *Game, s *seat, shuffled bool) {
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		bet := s.ai.Bet(shuffled)
+		sideBets := askSideBets(g, s)
+		err := checkBet(g, s, bet, sideBets)
+		if err == nil {
+			s.bet, s.sideBets = bet, sideBets
+			return
+		}
+		reject(s, err)
+	}
+	s.bet, s.sideBets = g.minBet, nil
+}
+
+// askSideBets asks the seat's AI for its side bets, if the table offers
+// any and the AI places them.
+func askSideBets(g *Game, s *seat) []int {
+	sai, ok := s.ai.(SideBetAI)
+	if len(g.sideBets) == 0 || !ok {
+		return nil
+	}
+	offered := make([]SideBet, len(g.sideBets))
+	copy(offered, g.sideBets)
+	return sai.SideBets(offered)
+}
 
-		for g.phase == dealerTurn {
-			hand := make([]deck.Card, len(g.dealer))
-			copy(hand, g.dealer)
-			move := g.dealerAI.Play(hand, hand[0])
-			move(g)
+func checkBet(g *Game, s *seat, bet int, sideBets []int) error {
+	if len(sideBets) > len(g.sideBets) {
+		return fmt.Errorf("%w: %d side bets placed, but the table offers %d", ErrInvalidBet, len(sideBets), len(g.sideBets))
+	}
+	total := bet
+	for i, wager := range sideBets {
+		if wager < 0 {
+			return fmt.Errorf("%w: side bet on %s can't be negative", ErrInvalidBet, g.sideBets[i].Name())
 		}
+		total += wager
+	}
 
-		endHand(g, player)
+	switch {
+	case bet < g.minBet:
+		return fmt.Errorf("%w: bet must be at least %d", ErrInvalidBet, g.minBet)
+	case g.maxBet > 0 && bet > g.maxBet:
+		return fmt.Errorf("%w: bet must be at most %d", ErrInvalidBet, g.maxBet)
+	case s.session.Bankroll > 0 && total > chips(s):
+		return fmt.Errorf("%w: bets of %d are more than your %d chips", ErrInvalidBet, total, chips(s))
 	}
+	return nil
+}
 
-	return g.balance
+// canAfford reports whether the seat has the chips to add the amount to
+// its bets.
+func canAfford(s *seat, amount int) bool {
+	return s.session.Bankroll == 0 || chips(s) >= amount
 }
 
-func bet(g *Game, ai AI, shuffled bool) {
-	bet := ai.Bet(shuffled)
-	if bet < 100 {
-		panic("bet must be at least 100")
+// chips returns the chips the seat has left to bet, which is only
+// meaningful if the seat has a bankroll.
+func chips(s *seat) int {
+	chips := s.session.Bankroll + s.balance - s.insured
+	for _, h := range s.hands {
+		chips -= h.bet
 	}
-	g.playerBet = bet
+	for _, wager := range s.sideBets {
+		chips -= wager
+	}
+	return chips
 }
 
-// deal deals two cards from the top of the deck to all players in
-// alternating order.
-func deal(g *Game) {
-	g.player = make([]deck.Card, 0, 5)
+// deal deals two cards from the top of the deck to each seat in turn
+// and then the dealer. Without a hole card, the dealer is dealt only
+// one.
+func deal(g *Game) error {
+	for _, s := range g.seats {
+		s.hands = []hand{{cards: make([]deck.Card, 0, 5), bet: s.bet}}
+	}
 	g.dealer = make([]deck.Card, 0, 5)
-	var card deck.Card
 	for i := 0; i < 2; i++ {
-		card, g.deck = draw(g.deck)
-		g.player = append(g.player, card)
-		card, g.deck = draw(g.deck)
-		g.dealer = append(g.dealer, card)
+		for _, s := range g.seats {
+			card, err := draw(g)
+			if err != nil {
+				return err
+			}
+			s.hands[0].cards = append(s.hands[0].cards, card)
+		}
+		if i == 0 || g.holeCard {
+			card, err := draw(g)
+			if err != nil {
+				return err
+			}
+			g.dealer = append(g.dealer, card)
+		}
 	}
+	for _, s := range g.seats {
+		s.dealt = copyCards(s.hands[0].cards)
+		reveal(g, s.index, 0, s.hands[0].cards...)
+	}
+	reveal(g, DealerSeat, 0, g.dealer[0])
+	g.seatIdx = 0
+	g.handIdx = 0
 	g.phase = playerTurn
+	return nil
+}
+
+// reveal shows cards turned face up on a hand at the seat with the
+// given place at the table, or the dealer's, to every seat watching the
+// table and to the game's observers.
+func reveal(g *Game, seat, hand int, cards ...deck.Card) {
+	for _, s := range g.seats {
+		if w, ok := s.ai.(WatcherAI); ok {
+			for _, c := range cards {
+				w.Seen(c)
+			}
+		}
+	}
+	for _, c := range cards {
+		emit(g, Event{Kind: CardDealt, Seat: seat, Hand: hand, Card: c})
+	}
+}
+
+// offerEarlySurrender lets the seat surrender before the dealer checks
+// for blackjack, if the rules and the seat's AI allow it.
+func offerEarlySurrender(g *Game, s *seat) {
+	sai, ok := s.ai.(EarlySurrenderAI)
+	if !g.earlySurrender || !ok {
+		return
+	}
+	h := &s.hands[0]
+	if sai.EarlySurrender(copyCards(h.cards), g.dealer[0]) {
+		h.surrendered = true
+	}
+}
+
+// offerInsurance offers the seat insurance against the dealer's ace, or
+// even money if it has a blackjack, if the rules and the seat's AI
+// allow it and the seat can afford it. Insurance costs half the
+// original bet.
+func offerInsurance(g *Game, s *seat) {
+	iai, ok := s.ai.(InsuranceAI)
+	h := &s.hands[0]
+	if !g.insurance || !ok || h.surrendered || g.dealer[0].Rank != deck.Ace || !canAfford(s, h.bet/2) {
+		return
+	}
+	if !iai.Insurance(copyCards(h.cards), g.dealer[0]) {
+		return
+	}
+	if Blackjack(h.cards...) {
+		h.evenMoney = true
+		return
+	}
+	s.insured = h.bet / 2
+}
+
+func copyCards(cards []deck.Card) []deck.Card {
+	ret := make([]deck.Card, len(cards))
+	copy(ret, cards)
+	return ret
+}
+
+// startHand readies the current hand for play, dealing the second card
+// to a hand made by splitting. Hands that leave the player no decision
+// to make, because they total 21 or are split aces that can't be hit,
+// are stood on automatically.
+func startHand(g *Game) error {
+	h := g.currentPlayerHand()
+	if h.surrendered || h.evenMoney {
+		return nextHand(g)
+	}
+	if len(h.cards) == 1 {
+		card, err := draw(g)
+		if err != nil {
+			return err
+		}
+		h.cards = append(h.cards, card)
+		reveal(g, g.seats[g.seatIdx].index, g.handIdx, card)
+	}
+	if Score(h.cards...) == 21 || h.splitAces && !g.hitSplitAces && !canSplit(g, h) {
+		return nextHand(g)
+	}
+	return nil
+}
+
+// nextHand moves play on to the seat's next hand, then to the next
+// seat, and finally to the dealer once every hand has been played.
+func nextHand(g *Game) error {
+	g.handIdx++
+	if g.handIdx >= len(g.seats[g.seatIdx].hands) {
+		g.seatIdx++
+		g.handIdx = 0
+	}
+	if g.seatIdx >= len(g.seats) {
+		g.phase = dealerTurn
+		return nil
+	}
+	return startHand(g)
+}
+
+// currentPlayerHand returns the player hand being played.
+func (g *Game) currentPlayerHand() *hand {
+	return &g.seats[g.seatIdx].hands[g.handIdx]
 }
 
 // Blackjack returns true if the hand is a blackjack.
@@ -153,44 +720,19 @@ func Blackjack(hand ...deck.Card) bool {
 	return len(hand) == 2 && Score(hand...) == 21
 }
 
+// scorer supplies the blackjack point values of cards.
+var scorer deck.BlackjackScorer
+
 // Soft returns true if the score of the hand is a soft score. I.e.
 // an ace is being counted as 11.
 func Soft(hand ...deck.Card) bool {
-	minScore := minScore(hand...)
-	score := Score(hand...)
-	return minScore != score
+	return scorer.Soft(hand...)
 }
 
 // Score returns the point value of the hand, converting aces between
 // 11 and 1 as appropriate.
 func Score(hand ...deck.Card) int {
-	minScore := minScore(hand...)
-	if minScore > 11 {
-		return minScore
-	}
-	for _, c := range hand {
-		if c.Rank == deck.Ace {
-			return minScore - 1 + 11 // ace is currently worth 1; change it to be worth 11
-		}
-	}
-	return minScore
-}
-
-// MinScore returns the point value of the hand, with any Aces counted
-// as 1.
-func minScore(hand ...deck.Card) int {
-	var score int
-	for _, c := range hand {
-		score += min(int(c.Rank), 10)
-	}
-	return score
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+	return scorer.Score(hand...)
 }
 
 // Move is an action taken by players or the dealer on their term, as
@@ -199,23 +741,129 @@ type Move func(*Game) error
 
 var (
 	errBust = errors.New("hand score exceeded 21")
+
+	// ErrInvalidMove is returned by moves the rules don't allow.
+	ErrInvalidMove = errors.New("blackjack: invalid move")
+	// ErrInvalidBet is returned for bets outside the table limits or
+	// the player's means.
+	ErrInvalidBet = errors.New("blackjack: invalid bet")
 )
 
+// invalidMove returns an ErrInvalidMove explaining why the move isn't
+// allowed.
+func invalidMove(format string, a ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidMove}, a...)...)
+}
+
+// MoveDouble doubles the bet on the current hand, which must have two
+// cards, and draws exactly one more card.
 func MoveDouble(g *Game) error {
-	if len(*g.currentHand()) != 2 {
-		return errors.New("can only double on a hand with 2 cards")
+	if g.phase != playerTurn {
+		return invalidMove("only players can double")
+	}
+	s := g.seats[g.seatIdx]
+	h := g.currentPlayerHand()
+	if len(h.cards) != 2 {
+		return invalidMove("can only double on a hand with 2 cards")
+	}
+	if h.split && !g.doubleAfterSplit {
+		return invalidMove("can't double after splitting")
+	}
+	if h.splitAces && !g.hitSplitAces {
+		return invalidMove("can't draw to split aces")
+	}
+	if !g.doubleOn.Allows(h.cards) {
+		return invalidMove("can't double on %d", Score(h.cards...))
 	}
-	g.playerBet *= 2
-	MoveHit(g)
+	if !canAfford(s, h.bet) {
+		return invalidMove("not enough chips to double")
+	}
+	h.bet *= 2
+	if err := MoveHit(g); err != nil && err != errBust {
+		return err
+	}
+	return MoveStand(g)
+}
+
+// MoveSplit splits the current hand, which must be a pair, into two
+// hands each carrying the original bet. Each new hand is dealt its
+// second card when its turn comes.
+func MoveSplit(g *Game) error {
+	if g.phase != playerTurn {
+		return invalidMove("only players can split")
+	}
+	s := g.seats[g.seatIdx]
+	h := s.hands[g.handIdx]
+	if len(h.cards) != 2 || h.cards[0].Rank != h.cards[1].Rank {
+		return invalidMove("can only split a pair")
+	}
+	if h.splitAces && !g.resplitAces {
+		return invalidMove("can't resplit aces")
+	}
+	if len(s.hands) >= g.maxSplitHands {
+		return invalidMove("can't split into more than %d hands", g.maxSplitHands)
+	}
+	if !canAfford(s, h.bet) {
+		return invalidMove("not enough chips to split")
+	}
+
+	aces := h.cards[0].Rank == deck.Ace
+	split := []hand{
+		{cards: h.cards[:1:1], bet: h.bet, split: true, splitAces: aces},
+		{cards: h.cards[1:2:2], bet: h.bet, split: true, splitAces: aces},
+	}
+	hands := make([]hand, 0, len(s.hands)+1)
+	hands = append(hands, s.hands[:g.handIdx]...)
+	hands = append(hands, split...)
+	hands = append(hands, s.hands[g.handIdx+1:]...)
+	s.hands = hands
+	return startHand(g)
+}
+
+// MoveSurrender gives up the current hand for half its bet. Only the
+// first two cards of an unsplit hand may be surrendered.
+func MoveSurrender(g *Game) error {
+	if !g.lateSurrender {
+		return invalidMove("surrender is not allowed")
+	}
+	if g.phase != playerTurn {
+		return invalidMove("only players can surrender")
+	}
+	h := g.currentPlayerHand()
+	if len(g.seats[g.seatIdx].hands) != 1 || len(h.cards) != 2 {
+		return invalidMove("can only surrender the first two cards")
+	}
+	h.surrendered = true
 	return MoveStand(g)
 }
 
+// canSplit reports whether the rules allow the hand to be split.
+func canSplit(g *Game, h *hand) bool {
+	if len(h.cards) != 2 || h.cards[0].Rank != h.cards[1].Rank {
+		return false
+	}
+	return (!h.splitAces || g.resplitAces) && len(g.seats[g.seatIdx].hands) < g.maxSplitHands
+}
+
 // MoveHit draws a new card and adds it to the current player's hand.
 func MoveHit(g *Game) error {
+	if g.phase == handOver {
+		return invalidMove("the round is over")
+	}
+	if g.phase == playerTurn && g.currentPlayerHand().splitAces && !g.hitSplitAces {
+		return invalidMove("can't draw to split aces")
+	}
+	card, err := draw(g)
+	if err != nil {
+		return err
+	}
 	hand := g.currentHand()
-	var card deck.Card
-	card, g.deck = draw(g.deck)
 	*hand = append(*hand, card)
+	if g.phase == playerTurn {
+		reveal(g, g.seats[g.seatIdx].index, g.handIdx, card)
+	} else {
+		reveal(g, DealerSeat, 0, card)
+	}
 	if Score(*hand...) >= 21 {
 		return errBust
 	}
@@ -224,59 +872,172 @@ func MoveHit(g *Game) error {
 
 // CurrentHand returns the hand of the player whose turn it is.
 func (g *Game) currentHand() *[]deck.Card {
-	switch g.phase {
-	case playerTurn:
-		return &g.player
-	case dealerTurn:
-		return &g.dealer
-	default:
-		panic("Current phase doesn't have a player turn")
+	if g.phase == playerTurn {
+		return &g.currentPlayerHand().cards
 	}
+	return &g.dealer
 }
 
-func draw(cards []deck.Card) (deck.Card, []deck.Card) {
-	return cards[0], cards[1:]
+// draw deals the next card from the shoe. If the shoe runs out mid-round,
+// it is refilled by shuffling the discards, and the whole shoe is
+// reshuffled before the next round.
+func draw(g *Game) (deck.Card, error) {
+	card, err := g.shoe.Draw()
+	if err != deck.ErrEmptyShoe {
+		return card, err
+	}
+	discards := g.shoe.Discards()
+	if len(discards) == 0 {
+		return card, fmt.Errorf("blackjack: %w, with no discards to reshuffle", err)
+	}
+	g.shoe = deck.NewShoe(g.shuffle(discards))
+	g.refilled = true
+	emit(g, Event{Kind: Reshuffled, Seat: DealerSeat})
+	return g.shoe.Draw()
 }
 
-// MoveStand starts the next phase of gameplay.
+// MoveStand ends the current hand, moving on to the player's next hand
+// or the next phase of gameplay.
 func MoveStand(g *Game) error {
 	switch g.phase {
 	case playerTurn:
-		g.phase = dealerTurn
+		return nextHand(g)
 	case dealerTurn:
 		g.phase = handOver
 	}
 	return nil
 }
 
-// endHand compares and prints the player scores along with the
-// outcome of the game, then clears the player hands.
-func endHand(g *Game, ai AI) {
-	pScore, dScore := Score(g.player...), Score(g.dealer...)
-	pBlackjack, dBlackjack := Blackjack(g.player...), Blackjack(g.dealer...)
-	winnings := g.playerBet
+// moves names the moves an AI can make. Moves are functions, which
+// can't be compared directly, so they are identified by their code
+// pointers.
+var moves = []struct {
+	name string
+	move Move
+}{
+	{"hit", MoveHit},
+	{"stand", MoveStand},
+	{"double", MoveDouble},
+	{"split", MoveSplit},
+	{"surrender", MoveSurrender},
+}
+
+// MoveName returns the name of the move: "hit", "stand", "double",
+// "split" or "surrender". It returns "" for nil or any other move.
+func MoveName(m Move) string {
+	for _, mv := range moves {
+		if reflect.ValueOf(m).Pointer() == reflect.ValueOf(mv.move).Pointer() {
+			return mv.name
+		}
+	}
+	return ""
+}
+
+// ParseMove returns the move named by MoveName, or nil if there is
+// none.
+func ParseMove(name string) Move {
+	for _, mv := range moves {
+		if mv.name == name {
+			return mv.move
+		}
+	}
+	return nil
+}
+
+// endHand settles the bets on each seat's hands against the dealer's,
+// then clears the hands.
+func endHand(g *Game) {
+	if g.holeCard {
+		reveal(g, DealerSeat, 0, g.dealer[1])
+	}
+	dBlackjack := Blackjack(g.dealer...)
+	for _, s := range g.seats {
+		result := Result{Dealer: copyCards(g.dealer)}
+		hands := make([][]deck.Card, len(s.hands))
+		for i, h := range s.hands {
+			winnings, outcome := winnings(g, s, h)
+			result.Hands = append(result.Hands, HandResult{
+				Cards:    h.cards,
+				Bet:      h.bet,
+				Winnings: winnings,
+				Outcome:  outcome,
+			})
+			result.Net += winnings
+			hands[i] = h.cards
+			g.shoe.Discard(h.cards...)
+		}
+		if s.insured > 0 {
+			result.Insurance = -s.insured
+			if dBlackjack {
+				result.Insurance = 2 * s.insured
+			}
+			result.Net += result.Insurance
+			s.insured = 0
+		}
+		result.SideBets = settleSideBets(g, s)
+		for _, sb := range result.SideBets {
+			result.Net += sb.Winnings
+		}
+		s.sideBets = nil
+		s.balance += result.Net
+
+		s.ai.Outcome(hands, g.dealer)
+		if rai, ok := s.ai.(ResultAI); ok {
+			rai.Result(result)
+		}
+		emit(g, Event{Kind: HandSettled, Seat: s.index, Result: &result})
+		s.hands = nil
+	}
+
+	g.shoe.Discard(g.dealer...)
+	g.dealer = nil
+}
+
+// settleSideBets settles the seat's side bets against its first two
+// cards and the dealer's hand.
+func settleSideBets(g *Game, s *seat) []SideBetResult {
+	var results []SideBetResult
+	for i, wager := range s.sideBets {
+		if wager == 0 {
+			continue
+		}
+		sb := g.sideBets[i]
+		winnings := -wager
+		if pays := sb.Pays(copyCards(s.dealt), copyCards(g.dealer)); pays > 0 {
+			winnings = wager * pays
+		}
+		results = append(results, SideBetResult{Name: sb.Name(), Bet: wager, Winnings: winnings})
+	}
+	return results
+}
+
+// winnings returns the amount won on the hand, which is negative if
+// the hand lost, and how it was settled. Only an unsplit hand can be a
+// blackjack.
+func winnings(g *Game, s *seat, h hand) (int, Outcome) {
+	pScore, dScore := Score(h.cards...), Score(g.dealer...)
+	pBlackjack := len(s.hands) == 1 && Blackjack(h.cards...)
+	dBlackjack := Blackjack(g.dealer...)
 	switch {
+	case h.surrendered:
+		return -h.bet / 2, Surrendered
+	case h.evenMoney:
+		return h.bet, Won
 	case pBlackjack && dBlackjack:
-		winnings = 0
+		return 0, Pushed
 	case dBlackjack:
-		winnings *= -1
+		return -h.bet, Lost
 	case pBlackjack:
-		winnings = int(float64(winnings) * g.blackjackPayout)
+		return int(float64(h.bet) * g.blackjackPayout), Natural
 	case pScore > 21:
-		winnings *= -1
+		return -h.bet, Lost
 	case dScore > 21:
-		// win
+		return h.bet, Won
 	case pScore > dScore:
-		// win
+		return h.bet, Won
 	case dScore > pScore:
-		winnings *= -1
-	case dScore == pScore:
-		winnings = 0
+		return -h.bet, Lost
+	default:
+		return 0, Pushed
 	}
-	fmt.Println()
-	g.balance += winnings
-
-	ai.Outcome([][]deck.Card{g.player}, g.dealer)
-	g.player = nil
-	g.dealer = nil
 }
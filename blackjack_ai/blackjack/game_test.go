@@ -0,0 +1,767 @@
+package blackjack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// scriptedAI bets a fixed amount and plays a fixed sequence of moves,
+// recording each hand it is asked to play.
+type scriptedAI struct {
+	bet            int
+	moves          []Move
+	insure         bool
+	surrenderEarly bool
+	played         [][]deck.Card
+	outcomes       [][]deck.Card
+	bets           int
+	rejected       []error
+}
+
+func (ai *scriptedAI) Bet(shuffled bool) int {
+	ai.bets++
+	return ai.bet
+}
+
+func (ai *scriptedAI) Rejected(err error) {
+	ai.rejected = append(ai.rejected, err)
+}
+
+func (ai *scriptedAI) Play(hand []deck.Card, dealer deck.Card) Move {
+	ai.played = append(ai.played, hand)
+	if len(ai.moves) == 0 {
+		return MoveStand
+	}
+	move := ai.moves[0]
+	ai.moves = ai.moves[1:]
+	return move
+}
+
+func (ai *scriptedAI) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	return ai.insure
+}
+
+func (ai *scriptedAI) EarlySurrender(hand []deck.Card, dealer deck.Card) bool {
+	return ai.surrenderEarly
+}
+
+func (ai *scriptedAI) Outcome(hands [][]deck.Card, dealer []deck.Card) {
+	ai.outcomes = hands
+}
+
+// play plays the game with the AI, failing the test on an error.
+func play(t *testing.T, g *Game, ai AI) int {
+	t.Helper()
+	balance, err := g.Play(ai)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return balance
+}
+
+func TestSplit(t *testing.T) {
+	// The player splits eights, doubles the first hand to 21 and stands
+	// on 17 with the second. The dealer stands on 17.
+	g := New(Options{
+		NDecks:  1,
+		NHands:  1,
+		Shuffle: deck.Stack("8S 10H 8D 7C 3S 10D 9S"),
+	})
+	ai := &scriptedAI{bet: 100, moves: []Move{MoveSplit, MoveDouble, MoveStand}}
+	if got, want := play(t, &g, ai), 200; got != want {
+		t.Errorf("balance is %d, want %d", got, want)
+	}
+	if len(ai.outcomes) != 2 {
+		t.Fatalf("player finished with %d hands, want 2", len(ai.outcomes))
+	}
+	if got := Score(ai.outcomes[1]...); got != 17 {
+		t.Errorf("second hand scored %d, want 17", got)
+	}
+}
+
+func TestSplitAcesReceiveOneCard(t *testing.T) {
+	g := New(Options{
+		NDecks:  1,
+		NHands:  1,
+		Shuffle: deck.Stack("AS 10H AD 8C 2S 3D"),
+	})
+	ai := &scriptedAI{bet: 100, moves: []Move{MoveSplit}}
+	play(t, &g, ai)
+	if len(ai.played) != 1 {
+		t.Errorf("AI was asked to play %d times, want once before splitting", len(ai.played))
+	}
+	for i, h := range ai.outcomes {
+		if len(h) != 2 {
+			t.Errorf("split ace hand %d has %d cards, want 2", i+1, len(h))
+		}
+	}
+}
+
+func TestSplitLimit(t *testing.T) {
+	g := New(Options{
+		NDecks:        1,
+		NHands:        1,
+		MaxSplitHands: 2,
+		Shuffle:       deck.Stack("8S 10H 8D 7C 8C"),
+	})
+	g.seats = []*seat{{hands: []hand{{cards: []deck.Card{{Rank: deck.Eight}, {Rank: deck.Eight}}, bet: 100}}}}
+	g.dealer = []deck.Card{{Rank: deck.Ten}, {Rank: deck.Seven}}
+	reshuffle(&g)
+	if err := MoveSplit(&g); err != nil {
+		t.Fatalf("first split: unexpected error: %v", err)
+	}
+	g.currentPlayerHand().cards = []deck.Card{{Rank: deck.Eight}, {Rank: deck.Eight}}
+	if err := MoveSplit(&g); err == nil {
+		t.Error("expected an error splitting beyond MaxSplitHands")
+	}
+}
+
+func TestInsuranceAndSurrender(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts Options
+		ai   *scriptedAI
+		top  string
+		want int
+	}{
+		{
+			name: "insurance against blackjack",
+			opts: Options{Insurance: true},
+			ai:   &scriptedAI{bet: 100, insure: true},
+			top:  "10S AH 9D KC",
+			want: 0, // lose 100, insurance wins 2 * 50
+		},
+		{
+			name: "insurance lost",
+			opts: Options{Insurance: true},
+			ai:   &scriptedAI{bet: 100, insure: true},
+			top:  "10S AH 10D 6C",
+			want: 50, // win 100 against 17, insurance loses 50
+		},
+		{
+			name: "insurance not offered",
+			ai:   &scriptedAI{bet: 100, insure: true},
+			top:  "10S AH 9D KC",
+			want: -100,
+		},
+		{
+			name: "even money",
+			opts: Options{Insurance: true},
+			ai:   &scriptedAI{bet: 100, insure: true},
+			top:  "AS AH KD KC",
+			want: 100,
+		},
+		{
+			name: "late surrender",
+			opts: Options{LateSurrender: true},
+			ai:   &scriptedAI{bet: 100, moves: []Move{MoveSurrender}},
+			top:  "10S 10H 6D 9C",
+			want: -50,
+		},
+		{
+			name: "early surrender against blackjack",
+			opts: Options{EarlySurrender: true},
+			ai:   &scriptedAI{bet: 100, surrenderEarly: true},
+			top:  "10S AH 6D KC",
+			want: -50,
+		},
+	}
+	for _, tc := range testCases {
+		tc.opts.NDecks = 1
+		tc.opts.NHands = 1
+		tc.opts.Shuffle = deck.Stack(tc.top)
+		g := New(tc.opts)
+		if got := play(t, &g, tc.ai); got != tc.want {
+			t.Errorf("%s: balance is %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSurrenderNotAllowed(t *testing.T) {
+	g := New(Options{NDecks: 1})
+	g.seats = []*seat{{hands: []hand{{cards: []deck.Card{{Rank: deck.Ten}, {Rank: deck.Six}}, bet: 100}}}}
+	if err := MoveSurrender(&g); err == nil {
+		t.Error("expected an error surrendering without LateSurrender")
+	}
+}
+
+func TestDealerSoft17(t *testing.T) {
+	// The player stands on 18 against the dealer's soft 17. The next card
+	// is a three, giving a dealer who hits soft 17 a total of 20.
+	testCases := []struct {
+		stands bool
+		want   int
+	}{
+		{stands: false, want: -100},
+		{stands: true, want: 100},
+	}
+	for _, tc := range testCases {
+		g := New(Options{
+			NDecks:             1,
+			NHands:             1,
+			DealerStandsSoft17: tc.stands,
+			Shuffle:            deck.Stack("10S AH 8D 6C 3S"),
+		})
+		if got := play(t, &g, &scriptedAI{bet: 100}); got != tc.want {
+			t.Errorf("DealerStandsSoft17 %t: balance is %d, want %d", tc.stands, got, tc.want)
+		}
+	}
+}
+
+func TestDoubleRule(t *testing.T) {
+	g := New(Options{NDecks: 1, DoubleOn: Double10To11})
+	g.seats = []*seat{{hands: []hand{{cards: []deck.Card{{Rank: deck.Five}, {Rank: deck.Four}}, bet: 100}}}}
+	if err := MoveDouble(&g); err == nil {
+		t.Error("expected an error doubling on 9 with Double10To11")
+	}
+}
+
+func TestNoHoleCard(t *testing.T) {
+	// The player doubles 11 to 21 but loses the doubled bet when the
+	// dealer draws a blackjack after the player's turn.
+	g := New(Options{
+		NDecks:     1,
+		NHands:     1,
+		NoHoleCard: true,
+		Shuffle:    deck.Stack("6S AH 5D 10C KS"),
+	})
+	if got, want := play(t, &g, &scriptedAI{bet: 100, moves: []Move{MoveDouble}}), -200; got != want {
+		t.Errorf("balance is %d, want %d", got, want)
+	}
+}
+
+func TestResplitAces(t *testing.T) {
+	g := New(Options{
+		NDecks:      1,
+		NHands:      1,
+		ResplitAces: true,
+		Shuffle:     deck.Stack("AS 10H AD 7C AC 9S 8D 7H"),
+	})
+	ai := &scriptedAI{bet: 100, moves: []Move{MoveSplit, MoveSplit}}
+	play(t, &g, ai)
+	if len(ai.outcomes) != 3 {
+		t.Errorf("player finished with %d hands, want 3", len(ai.outcomes))
+	}
+}
+
+func TestPlayTable(t *testing.T) {
+	// Cards go to each seat in turn, then the dealer: the first seat is
+	// dealt 20, the second 17, and the dealer 17.
+	g := New(Options{
+		NDecks:  1,
+		NHands:  1,
+		Shuffle: deck.Stack("10S 9S 10H 10D 8C 7H"),
+	})
+	first, second := &scriptedAI{bet: 100}, &scriptedAI{bet: 200}
+	balances, err := g.PlayTable(first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{100, 0}; balances[0] != want[0] || balances[1] != want[1] {
+		t.Errorf("balances are %v, want %v", balances, want)
+	}
+	if got := Score(second.played[0]...); got != 17 {
+		t.Errorf("second seat was dealt %d, want 17", got)
+	}
+}
+
+// watcherAI is a scriptedAI that records the cards it sees revealed.
+type watcherAI struct {
+	scriptedAI
+	seen []deck.Card
+}
+
+func (ai *watcherAI) Seen(card deck.Card) {
+	ai.seen = append(ai.seen, card)
+}
+
+func TestWatcherAI(t *testing.T) {
+	// The second seat hits 17 and busts with a five. The dealer's hole
+	// card, a seven, is revealed last.
+	g := New(Options{
+		NDecks:  1,
+		NHands:  1,
+		Shuffle: deck.Stack("10S 9S 10H 10D 8C 7H 5C"),
+	})
+	watcher := &watcherAI{scriptedAI: scriptedAI{bet: 100}}
+	if _, err := g.PlayTable(watcher, &scriptedAI{bet: 100, moves: []Move{MoveHit}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range watcher.seen {
+		got = append(got, c.ShortString())
+	}
+	want := []string{"10S", "10D", "9S", "8C", "10H", "5C", "7H"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("saw %v, want %v", got, want)
+	}
+}
+
+func TestPlayTableSeats(t *testing.T) {
+	players := make([]AI, MaxSeats+1)
+	for i := range players {
+		players[i] = &scriptedAI{bet: 100}
+	}
+	g := New(Options{NHands: 1})
+	if _, err := g.PlayTable(players...); err == nil {
+		t.Error("expected an error seating more than MaxSeats players")
+	}
+}
+
+func TestInvalidMoveRetried(t *testing.T) {
+	// Surrender isn't allowed, so the player is asked again and stands on
+	// 16 against the dealer's 17.
+	g := New(Options{
+		NDecks:  1,
+		NHands:  1,
+		Shuffle: deck.Stack("10S 10H 6D 7C"),
+	})
+	ai := &scriptedAI{bet: 100, moves: []Move{MoveSurrender, MoveStand}}
+	if got, want := play(t, &g, ai), -100; got != want {
+		t.Errorf("balance is %d, want %d", got, want)
+	}
+	if len(ai.rejected) != 1 || !errors.Is(ai.rejected[0], ErrInvalidMove) {
+		t.Errorf("rejected with %v, want one ErrInvalidMove", ai.rejected)
+	}
+
+	// After too many invalid moves, the hand is stood on for the player.
+	g = New(Options{NDecks: 1, NHands: 1, Shuffle: deck.Stack("10S 10H 6D 7C")})
+	ai = &scriptedAI{bet: 100, moves: []Move{MoveSurrender, MoveSurrender, MoveSurrender, MoveHit}}
+	if got, want := play(t, &g, ai), -100; got != want {
+		t.Errorf("balance after %d invalid moves is %d, want %d", maxAttempts, got, want)
+	}
+	if len(ai.rejected) != maxAttempts || len(ai.moves) != 1 {
+		t.Errorf("rejected %d times with %d moves left, want %d rejections and the hit unplayed", len(ai.rejected), len(ai.moves), maxAttempts)
+	}
+}
+
+func TestInvalidBet(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts Options
+		bet  int
+	}{
+		{"below minimum", Options{MinBet: 50}, 25},
+		{"above maximum", Options{MaxBet: 500}, 1000},
+		{"above bankroll", Options{Bankroll: 300}, 400},
+	}
+	for _, tc := range testCases {
+		tc.opts.NHands = 1
+		g := New(tc.opts)
+		placed := 0
+		g.Observe(ObserverFunc(func(e Event) {
+			if e.Kind == HandStarted {
+				placed = e.Bet
+			}
+		}))
+		ai := &scriptedAI{bet: tc.bet}
+		play(t, &g, ai)
+		if ai.bets != maxAttempts || len(ai.rejected) != maxAttempts || !errors.Is(ai.rejected[0], ErrInvalidBet) {
+			t.Errorf("%s: asked to bet %d times and rejected with %v", tc.name, ai.bets, ai.rejected)
+		}
+		if want := tc.opts.WithDefaults().MinBet; placed != want {
+			t.Errorf("%s: bet %d after %d invalid bets, want the minimum %d", tc.name, placed, maxAttempts, want)
+		}
+	}
+}
+
+func TestInvalidBetAtTable(t *testing.T) {
+	// A seat that only makes invalid bets and moves doesn't stop the rest
+	// of the table playing every round.
+	g := New(Options{NHands: 20, MaxBet: 500})
+	bad := &scriptedAI{bet: 1000, moves: []Move{MoveSurrender, MoveSurrender, MoveSurrender}}
+	good := &scriptedAI{bet: 100}
+	if _, err := g.PlayTable(bad, good); err != nil {
+		t.Fatal(err)
+	}
+	if bad.bets != 20*maxAttempts || good.bets != 20 {
+		t.Errorf("seats asked to bet %d and %d times, want %d and 20", bad.bets, good.bets, 20*maxAttempts)
+	}
+}
+
+func TestBankroll(t *testing.T) {
+	// The player can't afford to double 11, so stands and loses to the
+	// dealer's 17, leaving too few chips to bet again.
+	g := New(Options{
+		NDecks:   1,
+		NHands:   5,
+		Bankroll: 150,
+		Shuffle:  deck.Stack("6S 10H 5D 7C"),
+	})
+	ai := &scriptedAI{bet: 100, moves: []Move{MoveDouble, MoveStand}}
+	if got, want := play(t, &g, ai), -100; got != want {
+		t.Errorf("balance is %d, want %d", got, want)
+	}
+	if len(ai.rejected) != 1 {
+		t.Errorf("rejected %d times, want once for doubling", len(ai.rejected))
+	}
+	if ai.bets != 1 {
+		t.Errorf("bet %d times, want once before leaving the table", ai.bets)
+	}
+}
+
+func TestSessionEnd(t *testing.T) {
+	testCases := []struct {
+		session Session
+		balance int
+		want    SessionEnd
+	}{
+		{Session{}, -1000000, InSession},
+		{Session{Bankroll: 500}, -450, Bust},
+		{Session{Bankroll: 500}, -400, InSession},
+		{Session{Bankroll: 500, StopLoss: 300}, -300, StoppedLoss},
+		{Session{WinGoal: 200}, 200, ReachedGoal},
+		{Session{WinGoal: 200}, 150, InSession},
+	}
+	for _, tc := range testCases {
+		if got := tc.session.End(tc.balance, 100); got != tc.want {
+			t.Errorf("%+v with a balance of %d: got %d, want %d", tc.session, tc.balance, got, tc.want)
+		}
+	}
+}
+
+// sessionAI is a scriptedAI with its own session rules.
+type sessionAI struct {
+	scriptedAI
+	session Session
+}
+
+func (ai *sessionAI) Session() Session {
+	return ai.session
+}
+
+func TestSessionRules(t *testing.T) {
+	// The first seat loses 100 standing on 16 against 17, and the second
+	// wins 100 standing on 20. Both then leave the table.
+	opts := Options{
+		NDecks:   1,
+		NHands:   5,
+		StopLoss: 100,
+		WinGoal:  1000,
+		Shuffle:  deck.Stack("10S 10H 10D 6D 10C 7C"),
+	}
+	g := New(opts)
+	loser := &scriptedAI{bet: 100}
+	winner := &sessionAI{scriptedAI: scriptedAI{bet: 100}, session: Session{WinGoal: 100}}
+	balances, err := g.PlayTable(loser, winner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balances[0] != -100 || balances[1] != 100 {
+		t.Errorf("balances are %v, want [-100 100]", balances)
+	}
+	if loser.bets != 1 || winner.bets != 1 {
+		t.Errorf("seats bet %d and %d times, want once each", loser.bets, winner.bets)
+	}
+}
+
+// sideBetAI is a scriptedAI that places fixed side bets.
+type sideBetAI struct {
+	scriptedAI
+	wagers []int
+}
+
+func (ai *sideBetAI) SideBets(offered []SideBet) []int {
+	return ai.wagers
+}
+
+// pairBet is a side bet paying 10 to 1 on a pair.
+type pairBet struct{}
+
+func (pairBet) Name() string { return "pair" }
+
+func (pairBet) Pays(hand []deck.Card, dealer []deck.Card) int {
+	if hand[0].Rank == hand[1].Rank {
+		return 10
+	}
+	return 0
+}
+
+// dealerBustBet is a side bet paying 2 to 1 on the dealer busting.
+type dealerBustBet struct{}
+
+func (dealerBustBet) Name() string { return "dealer-bust" }
+
+func (dealerBustBet) Pays(hand []deck.Card, dealer []deck.Card) int {
+	if Score(dealer...) > 21 {
+		return 2
+	}
+	return 0
+}
+
+func TestSideBets(t *testing.T) {
+	// The player splits eights, keeping the first two cards for the
+	// side bets, and loses both hands of 18 to the dealer's 19.
+	g := New(Options{
+		NDecks:   1,
+		NHands:   1,
+		Shuffle:  deck.Stack("8S 10H 8D 9C 10S 10D"),
+		SideBets: []SideBet{pairBet{}, dealerBustBet{}},
+	})
+	var result *Result
+	g.Observe(ObserverFunc(func(e Event) {
+		if e.Kind == HandSettled {
+			result = e.Result
+		}
+	}))
+	ai := &sideBetAI{scriptedAI: scriptedAI{bet: 100, moves: []Move{MoveSplit}}, wagers: []int{10, 20}}
+	if got, want := play(t, &g, ai), -200+100-20; got != want {
+		t.Errorf("balance is %d, want %d", got, want)
+	}
+	want := []SideBetResult{{"pair", 10, 100}, {"dealer-bust", 20, -20}}
+	if result == nil || !reflect.DeepEqual(result.SideBets, want) {
+		t.Errorf("side bets settled as %+v, want %+v", result, want)
+	}
+}
+
+func TestWrapper(t *testing.T) {
+	// The wrapped AI places its side bets and takes its rejections just
+	// as it would unwrapped.
+	g := New(Options{
+		NDecks:   1,
+		NHands:   1,
+		Shuffle:  deck.Stack("8S 10H 8D 9C 10S 10D"),
+		SideBets: []SideBet{pairBet{}},
+	})
+	ai := &sideBetAI{scriptedAI: scriptedAI{bet: 100, moves: []Move{MoveSurrender, MoveSplit}}, wagers: []int{10}}
+	if got, want := play(t, &g, Wrapper{AI: ai}), -200+100; got != want {
+		t.Errorf("balance is %d, want %d", got, want)
+	}
+	if len(ai.rejected) != 1 {
+		t.Errorf("wrapped AI was told of %d rejections, want 1", len(ai.rejected))
+	}
+
+	w := Wrapper{AI: dealerAI{}}
+	if w.Insurance(nil, deck.Card{}) || w.EarlySurrender(nil, deck.Card{}) || w.SideBets([]SideBet{pairBet{}}) != nil || w.Session() != (Session{}) {
+		t.Error("wrapper made decisions for an AI that doesn't make them")
+	}
+}
+
+func TestMoveName(t *testing.T) {
+	for _, name := range []string{"hit", "stand", "double", "split", "surrender"} {
+		if got := MoveName(ParseMove(name)); got != name {
+			t.Errorf("%s: parsed and named as %q", name, got)
+		}
+	}
+	if m := ParseMove("fold"); m != nil {
+		t.Error("parsed an unknown move")
+	}
+	if got := MoveName(nil); got != "" {
+		t.Errorf("named no move %q, want \"\"", got)
+	}
+}
+
+func TestInvalidSideBet(t *testing.T) {
+	testCases := []struct {
+		name   string
+		wagers []int
+	}{
+		{"negative", []int{-10}},
+		{"not offered", []int{10, 10}},
+		{"above bankroll", []int{60}},
+	}
+	for _, tc := range testCases {
+		g := New(Options{NHands: 1, Bankroll: 150, SideBets: []SideBet{pairBet{}}})
+		var result *Result
+		g.Observe(ObserverFunc(func(e Event) {
+			if e.Kind == HandSettled {
+				result = e.Result
+			}
+		}))
+		ai := &sideBetAI{scriptedAI: scriptedAI{bet: 100}, wagers: tc.wagers}
+		play(t, &g, ai)
+		if len(ai.rejected) != maxAttempts || !errors.Is(ai.rejected[0], ErrInvalidBet) {
+			t.Errorf("%s: rejected with %v, want %d ErrInvalidBets", tc.name, ai.rejected, maxAttempts)
+		}
+		if result == nil || len(result.SideBets) != 0 {
+			t.Errorf("%s: settled %+v, want no side bets", tc.name, result)
+		}
+	}
+}
+
+func TestDrawRefillsShoe(t *testing.T) {
+	g := New(Options{NDecks: 1})
+	g.shoe = deck.NewShoe([]deck.Card{{Rank: deck.Two, Suit: deck.Spades}})
+	card, err := draw(&g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := draw(&g); !errors.Is(err, deck.ErrEmptyShoe) {
+		t.Errorf("drawing from an empty shoe with no discards: got error %v, want ErrEmptyShoe", err)
+	}
+
+	g.shoe.Discard(card)
+	got, err := draw(&g)
+	if err != nil {
+		t.Fatalf("drawing from an empty shoe with discards: %v", err)
+	}
+	if got != card {
+		t.Errorf("drew %s from the refilled shoe, want %s", got, card)
+	}
+	if !g.refilled {
+		t.Error("refilled shoe not marked for a reshuffle")
+	}
+}
+
+// describe summarises the event for comparison.
+func describe(e Event) string {
+	switch e.Kind {
+	case HandStarted:
+		return fmt.Sprintf("%s %d %d", e.Kind, e.Seat, e.Bet)
+	case CardDealt:
+		return fmt.Sprintf("%s %d %s", e.Kind, e.Seat, e.Card.ShortString())
+	case MoveMade:
+		return fmt.Sprintf("%s %d %s", e.Kind, e.Seat, MoveName(e.Move))
+	case HandSettled:
+		return fmt.Sprintf("%s %d %d", e.Kind, e.Seat, e.Result.Net)
+	}
+	return fmt.Sprintf("%s %d", e.Kind, e.Seat)
+}
+
+func TestObserver(t *testing.T) {
+	// The second seat hits 17 and busts with a five. The dealer stands
+	// on 17, beating the first seat's 20, and its hole card, a seven,
+	// is dealt last.
+	g := New(Options{
+		NDecks:  1,
+		NHands:  1,
+		Shuffle: deck.Stack("10S 9S 10H 10D 8C 7H 5C"),
+	})
+	var got []string
+	g.Observe(ObserverFunc(func(e Event) {
+		if e.Round != 1 {
+			t.Errorf("%s in round %d, want 1", e.Kind, e.Round)
+		}
+		got = append(got, describe(e))
+	}))
+	if _, err := g.PlayTable(&scriptedAI{bet: 100}, &scriptedAI{bet: 200, moves: []Move{MoveHit}}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Reshuffled -1",
+		"HandStarted 0 100",
+		"HandStarted 1 200",
+		"CardDealt 0 10S",
+		"CardDealt 0 10D",
+		"CardDealt 1 9S",
+		"CardDealt 1 8C",
+		"CardDealt -1 10H",
+		"MoveMade 0 stand",
+		"CardDealt 1 5C",
+		"MoveMade 1 hit",
+		"MoveMade -1 stand",
+		"CardDealt -1 7H",
+		"HandSettled 0 100",
+		"HandSettled 1 -200",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got events:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPlayContextCancelled(t *testing.T) {
+	// Cancelling mid-round lets the round finish before the game stops.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g := New(Options{NHands: 10})
+	settled := 0
+	g.Observe(ObserverFunc(func(e Event) {
+		if e.Kind == HandStarted && e.Round == 3 {
+			cancel()
+		}
+		if e.Kind == HandSettled {
+			settled++
+		}
+	}))
+	ai := &scriptedAI{bet: 100}
+	if _, err := g.PlayContext(ctx, ai); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if ai.bets != 3 || settled != 3 {
+		t.Errorf("played %d rounds and settled %d, want 3", ai.bets, settled)
+	}
+}
+
+func TestObserverSeatAfterBust(t *testing.T) {
+	// The first seat loses its whole bankroll standing on 16 against 17
+	// and leaves the table. The second seat keeps its place in the
+	// events of the next round.
+	g := New(Options{
+		NDecks:  1,
+		NHands:  2,
+		Shuffle: deck.Stack("10S 10H 10C 6H 10D 7C"),
+	})
+	seats := map[int][]int{}
+	g.Observe(ObserverFunc(func(e Event) {
+		if e.Seat != DealerSeat {
+			seats[e.Round] = append(seats[e.Round], e.Seat)
+		}
+	}))
+	bust := &sessionAI{scriptedAI: scriptedAI{bet: 100}, session: Session{Bankroll: 100}}
+	if _, err := g.PlayTable(bust, &scriptedAI{bet: 200}); err != nil {
+		t.Fatal(err)
+	}
+	if len(seats[2]) == 0 {
+		t.Fatal("no events at the second seat in round 2")
+	}
+	for _, seat := range seats[2] {
+		if seat != 1 {
+			t.Errorf("got an event at seat %d in round 2, want only seat 1", seat)
+		}
+	}
+}
+
+func TestReshuffleKeepsOrder(t *testing.T) {
+	// Without shuffling, the next shoe holds the discards in the order
+	// they were discarded, followed by the cards left undealt.
+	g := New(Options{NDecks: 1, Shuffle: func(rand.Source) deck.Option {
+		return func(cards []deck.Card) []deck.Card { return cards }
+	}})
+	reshuffle(&g)
+	first, err := g.shoe.DrawN(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.shoe.Discard(first[3], first[1], first[0], first[2])
+	reshuffle(&g)
+	want := append([]deck.Card{first[3], first[1], first[0], first[2]}, deck.New()[4:]...)
+	got, err := g.shoe.DrawN(len(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reshuffled shoe begins %v, want %v", got[:6], want[:6])
+	}
+}
+
+func TestSeedZero(t *testing.T) {
+	// A game seeded with 0 is dealt the same cards each time it's
+	// played, rather than shuffled from the clock.
+	deal := func() (string, int64) {
+		g := New(Options{NHands: 5, Seed: Seed(0)})
+		var dealt []string
+		g.Observe(ObserverFunc(func(e Event) {
+			if e.Kind == CardDealt {
+				dealt = append(dealt, e.Card.ShortString())
+			}
+		}))
+		play(t, &g, &scriptedAI{bet: 100})
+		return strings.Join(dealt, " "), g.Seed()
+	}
+	first, seed := deal()
+	if seed != 0 {
+		t.Errorf("game reports seed %d, want 0", seed)
+	}
+	if second, _ := deal(); second != first {
+		t.Errorf("seed 0 dealt %s, then %s", first, second)
+	}
+}
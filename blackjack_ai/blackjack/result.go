@@ -0,0 +1,41 @@
+//go:generate stringer -type=Outcome
+
+package blackjack
+
+import "github.com/angusgmorrison/gophercises/deck"
+
+// Outcome is the way a single hand was settled.
+type Outcome uint8
+
+const (
+	Lost        Outcome = iota
+	Pushed              // the bet was returned
+	Won                 // including taking even money on a blackjack
+	Natural             // won with a blackjack, paid at the blackjack payout
+	Surrendered         // half the bet was given up
+)
+
+// HandResult is the settlement of one of a player's hands.
+type HandResult struct {
+	Cards    []deck.Card
+	Bet      int
+	Winnings int // the amount won, which is negative if the hand lost
+	Outcome  Outcome
+}
+
+// Result is the settlement of all of a player's bets at the end of a
+// round.
+type Result struct {
+	Hands     []HandResult // more than one if the player split
+	Dealer    []deck.Card
+	Insurance int             // the amount won on insurance, which is negative if it lost
+	SideBets  []SideBetResult // the side bets placed, if any
+	Net       int             // the total won across all hands, insurance and side bets
+}
+
+// SideBetResult is the settlement of one of a player's side bets.
+type SideBetResult struct {
+	Name     string
+	Bet      int
+	Winnings int // the amount won, which is negative if the side bet lost
+}
@@ -0,0 +1,16 @@
+package blackjack
+
+import "github.com/angusgmorrison/gophercises/deck"
+
+// SideBet is a wager placed alongside a player's bet on the cards they
+// are first dealt, which is settled at the end of the round whatever
+// becomes of their hands.
+type SideBet interface {
+	// Name identifies the side bet in results and reports.
+	Name() string
+	// Pays returns the multiple of the wager won on a player's first
+	// two cards and the dealer's hand at settlement, upcard first, or 0
+	// if the wager loses. Without a hole card, the dealer may hold only
+	// the upcard if no player hand was left to play against.
+	Pays(hand []deck.Card, dealer []deck.Card) int
+}
@@ -0,0 +1,142 @@
+// Package counting keeps card counts, which track how rich the cards
+// left in a blackjack shoe are in high cards, and turns them into bets.
+package counting
+
+import (
+	"math"
+
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// A System is a card counting system, which tags each rank with a
+// value to add to the count when a card of that rank is seen.
+type System struct {
+	Name string
+	Tags [deck.King + 1]int // indexed by rank
+	// Balanced systems' tags sum to zero over a deck, so their running
+	// count is divided by the decks left to give the true count.
+	// Unbalanced systems are played on the running count alone.
+	Balanced bool
+	// IRC returns the initial running count for a shoe of the given
+	// number of decks. Balanced systems start from zero.
+	IRC func(decks int) int
+	// Ramp is a suggested 1-8 bet spread for the system.
+	Ramp Ramp
+}
+
+// HiLo is the Hi-Lo system: 2-6 count +1, 7-9 count 0 and tens and aces
+// count -1.
+var HiLo = System{
+	Name: "Hi-Lo",
+	Tags: [...]int{
+		deck.Ace: -1, deck.Two: 1, deck.Three: 1, deck.Four: 1, deck.Five: 1, deck.Six: 1,
+		deck.Ten: -1, deck.Jack: -1, deck.Queen: -1, deck.King: -1,
+	},
+	Balanced: true,
+	Ramp:     Ramp{{2, 2}, {3, 4}, {4, 6}, {5, 8}},
+}
+
+// KO is the unbalanced Knock-Out system, which counts 7 as +1 like the
+// other low cards. Its initial running count puts the pivot, where the
+// running count matches the Hi-Lo true count, at +4.
+var KO = System{
+	Name: "KO",
+	Tags: [...]int{
+		deck.Ace: -1, deck.Two: 1, deck.Three: 1, deck.Four: 1, deck.Five: 1, deck.Six: 1, deck.Seven: 1,
+		deck.Ten: -1, deck.Jack: -1, deck.Queen: -1, deck.King: -1,
+	},
+	IRC:  func(decks int) int { return 4 - 4*decks },
+	Ramp: Ramp{{1, 2}, {2, 4}, {3, 6}, {4, 8}},
+}
+
+// OmegaII is the level-two Omega II system, which tags 4-6 +2 and tens
+// -2, and ignores aces.
+var OmegaII = System{
+	Name: "Omega II",
+	Tags: [...]int{
+		deck.Two: 1, deck.Three: 1, deck.Four: 2, deck.Five: 2, deck.Six: 2, deck.Seven: 1,
+		deck.Nine: -1, deck.Ten: -2, deck.Jack: -2, deck.Queen: -2, deck.King: -2,
+	},
+	Balanced: true,
+	Ramp:     Ramp{{4, 2}, {6, 4}, {8, 6}, {10, 8}},
+}
+
+// Systems lists the built-in counting systems.
+var Systems = []System{HiLo, KO, OmegaII}
+
+// Counter keeps the count of a shoe.
+type Counter struct {
+	system  System
+	decks   int
+	running int
+	seen    int
+}
+
+// NewCounter returns a counter for a freshly shuffled shoe of the given
+// number of decks.
+func NewCounter(system System, decks int) *Counter {
+	c := &Counter{system: system, decks: decks}
+	c.Reset()
+	return c
+}
+
+// Reset restarts the count after a shuffle.
+func (c *Counter) Reset() {
+	c.running, c.seen = 0, 0
+	if c.system.IRC != nil {
+		c.running = c.system.IRC(c.decks)
+	}
+}
+
+// Seen adds a card to the count.
+func (c *Counter) Seen(card deck.Card) {
+	if card.Suit != deck.Joker && card.Rank <= deck.King {
+		c.running += c.system.Tags[card.Rank]
+	}
+	c.seen++
+}
+
+// Running returns the running count.
+func (c *Counter) Running() int {
+	return c.running
+}
+
+// DecksRemaining returns the number of decks not yet seen, at least half
+// a deck, as a counter would estimate it from the discard tray.
+func (c *Counter) DecksRemaining() float64 {
+	return math.Max(float64(c.decks*52-c.seen)/52, minDecksRemaining)
+}
+
+const minDecksRemaining = 0.5
+
+// True returns the true count: the running count per deck remaining for
+// balanced systems, or the running count itself for unbalanced ones.
+func (c *Counter) True() float64 {
+	if !c.system.Balanced {
+		return float64(c.running)
+	}
+	return float64(c.running) / c.DecksRemaining()
+}
+
+// A Ramp sets bets, in units, by the count. Its steps are in ascending
+// order of count.
+type Ramp []Step
+
+// Step is a bet in a Ramp: Units units at Count or higher.
+type Step struct {
+	Count float64
+	Units int
+}
+
+// Units returns the bet for the count: that of the highest step it
+// reaches, or a single unit if it reaches none.
+func (r Ramp) Units(count float64) int {
+	units := 1
+	for _, s := range r {
+		if count < s.Count {
+			break
+		}
+		units = s.Units
+	}
+	return units
+}
@@ -0,0 +1,63 @@
+package counting
+
+import (
+	"testing"
+
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+func TestSystems(t *testing.T) {
+	// Balanced systems count a whole deck back to zero; KO finishes a
+	// deck four above its initial running count.
+	want := map[string]int{"Hi-Lo": 0, "KO": 4, "Omega II": 0}
+	for _, s := range Systems {
+		c := NewCounter(s, 1)
+		irc := c.Running()
+		for _, card := range deck.New() {
+			c.Seen(card)
+		}
+		if got := c.Running() - irc; got != want[s.Name] {
+			t.Errorf("%s: a deck counts %+d, want %+d", s.Name, got, want[s.Name])
+		}
+	}
+}
+
+func TestCounter(t *testing.T) {
+	c := NewCounter(HiLo, 2)
+	for i := 0; i < 26; i++ {
+		c.Seen(deck.Card{Rank: deck.Five, Suit: deck.Hearts})
+	}
+	if c.Running() != 26 {
+		t.Errorf("running count is %d, want 26", c.Running())
+	}
+	if got := c.DecksRemaining(); got != 1.5 {
+		t.Errorf("%.2f decks remaining, want 1.5", got)
+	}
+	if got, want := c.True(), 26/1.5; got != want {
+		t.Errorf("true count is %.2f, want %.2f", got, want)
+	}
+	c.Reset()
+	if c.Running() != 0 || c.DecksRemaining() != 2 {
+		t.Errorf("after reset, running count is %d with %.2f decks remaining, want 0 with 2",
+			c.Running(), c.DecksRemaining())
+	}
+
+	ko := NewCounter(KO, 6)
+	if got, want := ko.True(), -20.0; got != want {
+		t.Errorf("KO count for 6 decks starts at %.0f, want %.0f", got, want)
+	}
+}
+
+func TestRamp(t *testing.T) {
+	testCases := []struct {
+		count float64
+		want  int
+	}{
+		{-3, 1}, {1.9, 1}, {2, 2}, {3.5, 4}, {4, 6}, {12, 8},
+	}
+	for _, tc := range testCases {
+		if got := HiLo.Ramp.Units(tc.count); got != tc.want {
+			t.Errorf("at %+.1f, bet %d units, want %d", tc.count, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Package history records blackjack games as hand histories, one JSON
+// object per line for each round played at each seat, and replays them
+// on exactly the same cards.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/sidebet"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// Round is the history of one round played at one seat.
+type Round struct {
+	Round int   `json:"round"` // counted from 1 for each seat
+	Seat  int   `json:"seat"`
+	Seed  int64 `json:"seed"` // the game's seed
+	Rules Rules `json:"rules"`
+	// Shuffles are the shoe's orders, from the top, after each shuffle
+	// since the previous round was recorded. A shoe that runs out
+	// mid-round is refilled by shuffling the discards, which is recorded
+	// as a shuffle of just those cards.
+	Shuffles [][]deck.Card `json:"shuffles,omitempty"`
+
+	Bet            int        `json:"bet"`
+	Insured        bool       `json:"insured,omitempty"`
+	EarlySurrender bool       `json:"early_surrender,omitempty"`
+	Decisions      []Decision `json:"decisions,omitempty"`
+	// Dealer is the dealer's final hand in the order it was dealt. Every
+	// card after the hole card was drawn by hitting.
+	Dealer    []deck.Card `json:"dealer"`
+	Hands     []Hand      `json:"hands"`
+	Insurance int         `json:"insurance,omitempty"` // the amount won on insurance
+	SideBets  []SideBet   `json:"side_bets,omitempty"`
+	Net       int         `json:"net"`
+}
+
+// Rules are the table rules a round was played by, as set in
+// blackjack.Options.
+type Rules struct {
+	NDecks             int                  `json:"decks"`
+	BlackjackPayout    float64              `json:"blackjack_payout"`
+	ReshuffleThreshold int                  `json:"reshuffle_threshold"`
+	MaxSplitHands      int                  `json:"max_split_hands"`
+	HitSplitAces       bool                 `json:"hit_split_aces,omitempty"`
+	NoDoubleAfterSplit bool                 `json:"no_double_after_split,omitempty"`
+	Insurance          bool                 `json:"insurance,omitempty"`
+	LateSurrender      bool                 `json:"late_surrender,omitempty"`
+	EarlySurrender     bool                 `json:"early_surrender,omitempty"`
+	DealerStandsSoft17 bool                 `json:"dealer_stands_soft_17,omitempty"`
+	DoubleOn           blackjack.DoubleRule `json:"double_on,omitempty"`
+	ResplitAces        bool                 `json:"resplit_aces,omitempty"`
+	NoHoleCard         bool                 `json:"no_hole_card,omitempty"`
+	MinBet             int                  `json:"min_bet"`
+	MaxBet             int                  `json:"max_bet,omitempty"`
+	Bankroll           int                  `json:"bankroll,omitempty"`
+	StopLoss           int                  `json:"stop_loss,omitempty"`
+	WinGoal            int                  `json:"win_goal,omitempty"`
+	SideBets           []string             `json:"side_bets,omitempty"` // the names of the side bets offered
+}
+
+// rules returns the table rules set in opts.
+func rules(opts blackjack.Options) Rules {
+	r := Rules{
+		NDecks:             opts.NDecks,
+		BlackjackPayout:    opts.BlackjackPayout,
+		ReshuffleThreshold: opts.ReshuffleThreshold,
+		MaxSplitHands:      opts.MaxSplitHands,
+		HitSplitAces:       opts.HitSplitAces,
+		NoDoubleAfterSplit: opts.NoDoubleAfterSplit,
+		Insurance:          opts.Insurance,
+		LateSurrender:      opts.LateSurrender,
+		EarlySurrender:     opts.EarlySurrender,
+		DealerStandsSoft17: opts.DealerStandsSoft17,
+		DoubleOn:           opts.DoubleOn,
+		ResplitAces:        opts.ResplitAces,
+		NoHoleCard:         opts.NoHoleCard,
+		MinBet:             opts.MinBet,
+		MaxBet:             opts.MaxBet,
+		Bankroll:           opts.Bankroll,
+		StopLoss:           opts.StopLoss,
+		WinGoal:            opts.WinGoal,
+	}
+	for _, sb := range opts.SideBets {
+		r.SideBets = append(r.SideBets, sb.Name())
+	}
+	return r
+}
+
+// Options returns the rules as options for a game, looking up the side
+// bets offered by name among those in package sidebet.
+func (r Rules) Options() (blackjack.Options, error) {
+	opts := blackjack.Options{
+		NDecks:             r.NDecks,
+		BlackjackPayout:    r.BlackjackPayout,
+		ReshuffleThreshold: r.ReshuffleThreshold,
+		MaxSplitHands:      r.MaxSplitHands,
+		HitSplitAces:       r.HitSplitAces,
+		NoDoubleAfterSplit: r.NoDoubleAfterSplit,
+		Insurance:          r.Insurance,
+		LateSurrender:      r.LateSurrender,
+		EarlySurrender:     r.EarlySurrender,
+		DealerStandsSoft17: r.DealerStandsSoft17,
+		DoubleOn:           r.DoubleOn,
+		ResplitAces:        r.ResplitAces,
+		NoHoleCard:         r.NoHoleCard,
+		MinBet:             r.MinBet,
+		MaxBet:             r.MaxBet,
+		Bankroll:           r.Bankroll,
+		StopLoss:           r.StopLoss,
+		WinGoal:            r.WinGoal,
+	}
+	for _, name := range r.SideBets {
+		sb, ok := sidebet.ByName(name)
+		if !ok {
+			return blackjack.Options{}, fmt.Errorf("history: unknown side bet %q", name)
+		}
+		opts.SideBets = append(opts.SideBets, sb)
+	}
+	return opts, nil
+}
+
+// Decision is a move made by a seat's AI.
+type Decision struct {
+	Hand   []deck.Card `json:"hand"`
+	Upcard deck.Card   `json:"upcard"`
+	Move   string      `json:"move"`
+	// Rejected is the reason the game rejected the move, if it did.
+	Rejected string `json:"rejected,omitempty"`
+}
+
+// Hand is the settlement of one of a seat's hands.
+type Hand struct {
+	Cards    []deck.Card `json:"cards"`
+	Bet      int         `json:"bet"`
+	Winnings int         `json:"winnings"`
+	Outcome  string      `json:"outcome"`
+}
+
+// SideBet is the settlement of one of a seat's side bets.
+type SideBet struct {
+	Name     string `json:"name"`
+	Bet      int    `json:"bet"`
+	Winnings int    `json:"winnings"`
+}
+
+// A Recorder records the rounds played by the AIs it seats.
+type Recorder struct {
+	record   func(Round) error
+	seed     int64
+	rules    Rules
+	seats    int
+	shuffles [][]deck.Card
+	err      error
+}
+
+// NewRecorder returns a Recorder writing each round to w as a line of
+// JSON.
+func NewRecorder(w io.Writer) *Recorder {
+	enc := json.NewEncoder(w)
+	return &Recorder{record: func(r Round) error { return enc.Encode(r) }}
+}
+
+// Options returns the options for the game to be recorded, which must be
+// passed to blackjack.New. The table rules and every shuffle are
+// recorded, and a seed is chosen from the clock if opts has none.
+func (r *Recorder) Options(opts blackjack.Options) blackjack.Options {
+	opts = opts.WithDefaults()
+	if opts.Seed == nil {
+		opts.Seed = blackjack.Seed(time.Now().UnixNano())
+	}
+	r.seed = *opts.Seed
+	r.rules = rules(opts)
+
+	shuffle := opts.Shuffle
+	opts.Shuffle = func(src rand.Source) deck.Option {
+		opt := shuffle(src)
+		return func(cards []deck.Card) []deck.Card {
+			cards = opt(cards)
+			r.shuffles = append(r.shuffles, append([]deck.Card(nil), cards...))
+			return cards
+		}
+	}
+	return opts
+}
+
+// Seat returns the AI wrapped to record its rounds. AIs must be seated
+// in the order they are passed to Game.PlayTable.
+func (r *Recorder) Seat(ai blackjack.AI) blackjack.AI {
+	s := &seat{Wrapper: blackjack.Wrapper{AI: ai}, recorder: r, seat: r.seats}
+	r.seats++
+	return s
+}
+
+// Err returns the first error encountered writing the history.
+func (r *Recorder) Err() error {
+	return r.err
+}
+
+// seat wraps an AI to record the rounds it plays.
+type seat struct {
+	blackjack.Wrapper
+	recorder *Recorder
+	seat     int
+	rounds   int
+	round    Round
+	open     bool // the round has been bet on but not yet settled
+	betting  bool // the AI was last asked for a bet rather than a move
+}
+
+func (s *seat) Bet(shuffled bool) int {
+	if !s.open {
+		s.rounds++
+		s.round = Round{Round: s.rounds, Seat: s.seat, Seed: s.recorder.seed, Rules: s.recorder.rules}
+		s.open = true
+	}
+	s.betting = true
+	s.round.Bet = s.AI.Bet(shuffled)
+	return s.round.Bet
+}
+
+func (s *seat) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	s.betting = false
+	move := s.AI.Play(hand, dealer)
+	s.round.Decisions = append(s.round.Decisions, Decision{
+		Hand:   hand,
+		Upcard: dealer,
+		Move:   blackjack.MoveName(move),
+	})
+	return move
+}
+
+// Insurance records whether the wrapped AI takes insurance.
+func (s *seat) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	s.round.Insured = s.Wrapper.Insurance(hand, dealer)
+	return s.round.Insured
+}
+
+// EarlySurrender records whether the wrapped AI surrenders early.
+func (s *seat) EarlySurrender(hand []deck.Card, dealer deck.Card) bool {
+	s.round.EarlySurrender = s.Wrapper.EarlySurrender(hand, dealer)
+	return s.round.EarlySurrender
+}
+
+// Rejected records the reason a move was rejected, and tells the
+// wrapped AI if it wants to know.
+func (s *seat) Rejected(err error) {
+	if n := len(s.round.Decisions); n > 0 && !s.betting {
+		s.round.Decisions[n-1].Rejected = err.Error()
+	}
+	s.Wrapper.Rejected(err)
+}
+
+func (s *seat) Result(res blackjack.Result) {
+	s.round.Dealer = res.Dealer
+	for _, h := range res.Hands {
+		s.round.Hands = append(s.round.Hands, Hand{
+			Cards:    h.Cards,
+			Bet:      h.Bet,
+			Winnings: h.Winnings,
+			Outcome:  h.Outcome.String(),
+		})
+	}
+	s.round.Insurance = res.Insurance
+	for _, sb := range res.SideBets {
+		s.round.SideBets = append(s.round.SideBets, SideBet{Name: sb.Name, Bet: sb.Bet, Winnings: sb.Winnings})
+	}
+	s.round.Net = res.Net
+	s.round.Shuffles, s.recorder.shuffles = s.recorder.shuffles, nil
+	s.open = false
+
+	if err := s.recorder.record(s.round); err != nil && s.recorder.err == nil {
+		s.recorder.err = err
+	}
+	s.Wrapper.Result(res)
+}
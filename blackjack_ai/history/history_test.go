@@ -0,0 +1,168 @@
+package history
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/sidebet"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/strategy"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// record plays a game with the players, returning its history as
+// written and as read back.
+func record(t *testing.T, opts blackjack.Options, players ...blackjack.AI) (string, []Round) {
+	t.Helper()
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	opts = rec.Options(opts)
+	for i, ai := range players {
+		players[i] = rec.Seat(ai)
+	}
+	g := blackjack.New(opts)
+	if _, err := g.PlayTable(players...); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Err(); err != nil {
+		t.Fatal(err)
+	}
+	log := buf.String()
+	history, err := Read(strings.NewReader(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return log, history
+}
+
+func TestRecord(t *testing.T) {
+	opts := blackjack.Options{NHands: 50, Seed: blackjack.Seed(1), Insurance: true, LateSurrender: true, SideBets: sidebet.All}
+	chart := strategy.ChartFor(opts)
+	wagers := map[string]int{"21+3": 5}
+	log, history := record(t, opts, strategy.NewAI(chart, opts).WithSideBets(wagers), strategy.NewAI(chart, opts))
+
+	if lines := strings.Count(log, "\n"); lines != 100 {
+		t.Fatalf("recorded %d lines, want one per round at each seat", lines)
+	}
+	if len(history[0].Shuffles) != 1 || len(history[0].Shuffles[0]) != 3*52 {
+		t.Errorf("first round recorded %d shuffles, want one of the whole shoe", len(history[0].Shuffles))
+	}
+	for _, r := range history {
+		if r.Seed != 1 {
+			t.Fatalf("round %d recorded seed %d, want 1", r.Round, r.Seed)
+		}
+		if want := rules(opts.WithDefaults()); !reflect.DeepEqual(r.Rules, want) {
+			t.Fatalf("round %d recorded rules %+v, want %+v", r.Round, r.Rules, want)
+		}
+		net := r.Insurance
+		for _, h := range r.Hands {
+			net += h.Winnings
+		}
+		for _, sb := range r.SideBets {
+			net += sb.Winnings
+		}
+		if want := 1 - r.Seat; len(r.SideBets) != want {
+			t.Errorf("round %d at seat %d recorded %d side bets, want %d", r.Round, r.Seat, len(r.SideBets), want)
+		}
+		if net != r.Net {
+			t.Errorf("round %d at seat %d: winnings sum to %d, but net is %d", r.Round, r.Seat, net, r.Net)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	opts := blackjack.Options{NHands: 200, Seed: blackjack.Seed(2), LateSurrender: true, MaxSplitHands: 3, SideBets: sidebet.All}
+	chart := strategy.ChartFor(opts)
+	_, history := record(t, opts, strategy.NewAI(chart, opts).WithSideBets(map[string]int{"perfect-pairs": 5}))
+	if err := Check(history); err != nil {
+		t.Fatalf("checking an untouched history: %v", err)
+	}
+
+	// Doctor a winning round to lose.
+	for i := range history {
+		if history[i].Net > 0 {
+			history[i].Net = -history[i].Net
+			break
+		}
+	}
+	if err := Check(history); err == nil || !strings.Contains(err.Error(), "net") {
+		t.Errorf("checking a doctored history: got error %v, want a different net", err)
+	}
+}
+
+func TestReplayUnknownSideBet(t *testing.T) {
+	opts := blackjack.Options{NHands: 5, Seed: blackjack.Seed(5), SideBets: sidebet.All}
+	_, history := record(t, opts, strategy.NewAI(strategy.ChartFor(opts), opts))
+	history[0].Rules.SideBets = append(history[0].Rules.SideBets, "royal-match")
+	if _, err := Replay(history); err == nil || !strings.Contains(err.Error(), "royal-match") {
+		t.Errorf("got error %v, want an unknown side bet", err)
+	}
+}
+
+func TestCheckRejectedMove(t *testing.T) {
+	// The player tries to double on three cards before standing.
+	opts := blackjack.Options{NHands: 20, Seed: blackjack.Seed(3)}
+	_, history := record(t, opts, &hitOnceAI{})
+	rejected := false
+	for _, r := range history {
+		for _, d := range r.Decisions {
+			rejected = rejected || d.Rejected != ""
+		}
+	}
+	if !rejected {
+		t.Fatal("no rejected moves recorded")
+	}
+	if err := Check(history); err != nil {
+		t.Error(err)
+	}
+}
+
+// hitOnceAI hits its first two cards, then tries to double before
+// standing.
+type hitOnceAI struct {
+	rejected bool
+}
+
+func (ai *hitOnceAI) Bet(shuffled bool) int {
+	ai.rejected = false
+	return 100
+}
+
+func (ai *hitOnceAI) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	switch {
+	case len(hand) == 2:
+		return blackjack.MoveHit
+	case len(hand) == 3 && !ai.rejected:
+		return blackjack.MoveDouble
+	}
+	return blackjack.MoveStand
+}
+
+func (ai *hitOnceAI) Rejected(err error) {
+	ai.rejected = true
+}
+
+func (ai *hitOnceAI) Outcome(hand [][]deck.Card, dealer []deck.Card) {}
+
+func TestReplayDifferentAI(t *testing.T) {
+	opts := blackjack.Options{NHands: 100, Seed: blackjack.Seed(4)}
+	_, history := record(t, opts, &hitOnceAI{})
+	replay, err := Replay(history, strategy.NewAI(strategy.ChartFor(opts), opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replay) != len(history) {
+		t.Fatalf("replayed %d rounds, want %d", len(replay), len(history))
+	}
+	// Both players are dealt the same cards off the top of the shoe.
+	want, got := history[0], replay[0]
+	if !sameCards(want.Hands[0].Cards[:2], got.Hands[0].Cards[:2]) || want.Dealer[0] != got.Dealer[0] {
+		t.Errorf("replay dealt %v against %v, want %v against %v",
+			got.Hands[0].Cards[:2], got.Dealer[0], want.Hands[0].Cards[:2], want.Dealer[0])
+	}
+	if !reflect.DeepEqual(want.Shuffles[0], got.Shuffles[0]) {
+		t.Error("replay shuffled the shoe differently")
+	}
+}
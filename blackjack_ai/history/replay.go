@@ -0,0 +1,222 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"reflect"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// Read reads a hand history written by a Recorder.
+func Read(r io.Reader) ([]Round, error) {
+	var rounds []Round
+	dec := json.NewDecoder(r)
+	for {
+		var round Round
+		err := dec.Decode(&round)
+		if err == io.EOF {
+			return rounds, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("history: round %d: %w", len(rounds)+1, err)
+		}
+		rounds = append(rounds, round)
+	}
+}
+
+// Replay plays the history's game again on the same cards, by the
+// table rules it was recorded with. Each seat is played by the AI given
+// for it, or, if there is none or it's nil, makes exactly the bets and
+// moves recorded. It returns the history of the replay.
+//
+// The shoe is shuffled into the recorded orders in turn. If the replay
+// shuffles a different set of cards, as when a different AI runs a shoe
+// dry where the original didn't, the cards are shuffled afresh instead.
+func Replay(history []Round, players ...blackjack.AI) ([]Round, error) {
+	if len(history) == 0 {
+		return nil, nil
+	}
+	seats, rounds := 0, 0
+	var shuffles [][]deck.Card
+	for _, r := range history {
+		if r.Seat >= seats {
+			seats = r.Seat + 1
+		}
+		if r.Round > rounds {
+			rounds = r.Round
+		}
+		shuffles = append(shuffles, r.Shuffles...)
+	}
+	if seats > blackjack.MaxSeats {
+		return nil, fmt.Errorf("history: %d seats recorded, but a table seats %d", seats, blackjack.MaxSeats)
+	}
+
+	opts, err := history[0].Rules.Options()
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.WithDefaults()
+	opts.Seed = blackjack.Seed(history[0].Seed)
+	opts.NHands = rounds
+	opts.Shuffle = replayShuffle(shuffles, opts.Shuffle)
+
+	var replay []Round
+	rec := &Recorder{record: func(r Round) error {
+		replay = append(replay, r)
+		return nil
+	}}
+	opts = rec.Options(opts)
+	ais := make([]blackjack.AI, seats)
+	for i := range ais {
+		if i < len(players) && players[i] != nil {
+			ais[i] = rec.Seat(players[i])
+		} else {
+			ais[i] = rec.Seat(Script(history, i))
+		}
+	}
+
+	g := blackjack.New(opts)
+	_, err = g.PlayTable(ais...)
+	return replay, err
+}
+
+// Check replays the history with the bets and moves recorded and returns
+// an error describing the first round that plays out differently.
+func Check(history []Round) error {
+	replay, err := Replay(history)
+	if err != nil {
+		return err
+	}
+	for i, want := range history {
+		if i >= len(replay) {
+			return fmt.Errorf("history: round %d at seat %d was not replayed", want.Round, want.Seat)
+		}
+		if field := diff(want, replay[i]); field != "" {
+			return fmt.Errorf("history: round %d at seat %d replayed with a different %s", want.Round, want.Seat, field)
+		}
+	}
+	if len(replay) > len(history) {
+		return fmt.Errorf("history: replay played %d more rounds", len(replay)-len(history))
+	}
+	return nil
+}
+
+// diff returns the name of the first field that differs between the
+// rounds, or "" if they're the same.
+func diff(a, b Round) string {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	for i := 0; i < va.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			return va.Type().Field(i).Tag.Get("json")
+		}
+	}
+	return ""
+}
+
+// replayShuffle returns an Options.Shuffle that shuffles cards into the
+// first of the recorded orders still to come that holds the same cards,
+// or with the fallback shuffle if none does.
+func replayShuffle(shuffles [][]deck.Card, fallback func(rand.Source) deck.Option) func(rand.Source) deck.Option {
+	return func(src rand.Source) deck.Option {
+		shuffle := fallback(src)
+		next := 0
+		return func(cards []deck.Card) []deck.Card {
+			for i := next; i < len(shuffles); i++ {
+				if sameCards(cards, shuffles[i]) {
+					next = i + 1
+					return append(cards[:0], shuffles[i]...)
+				}
+			}
+			return shuffle(cards)
+		}
+	}
+}
+
+// sameCards reports whether a and b hold the same cards in any order.
+func sameCards(a, b []deck.Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[deck.Card]int, len(a))
+	for _, c := range a {
+		counts[c]++
+	}
+	for _, c := range b {
+		if counts[c] == 0 {
+			return false
+		}
+		counts[c]--
+	}
+	return true
+}
+
+// Script returns an AI that makes the bets, side bets and moves recorded
+// for the seat, in order, whatever cards it is dealt. Once the moves recorded
+// for a round run out, it stands.
+func Script(history []Round, seat int) blackjack.AI {
+	s := &script{}
+	for _, r := range history {
+		if r.Seat == seat {
+			s.rounds = append(s.rounds, r)
+		}
+	}
+	return s
+}
+
+// script is an AI replaying the rounds recorded for a seat.
+type script struct {
+	rounds []Round
+	round  int // index in rounds of the round being played
+	move   int // index in the round's decisions of the next move
+}
+
+// current returns the round being played, or an empty round if the
+// script has run out.
+func (s *script) current() Round {
+	if s.round < len(s.rounds) {
+		return s.rounds[s.round]
+	}
+	return Round{}
+}
+
+func (s *script) Bet(shuffled bool) int {
+	return s.current().Bet
+}
+
+func (s *script) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	decisions := s.current().Decisions
+	if s.move >= len(decisions) {
+		return blackjack.MoveStand
+	}
+	s.move++
+	return blackjack.ParseMove(decisions[s.move-1].Move)
+}
+
+func (s *script) SideBets(offered []blackjack.SideBet) []int {
+	wagers := make([]int, len(offered))
+	for _, sb := range s.current().SideBets {
+		for i, o := range offered {
+			if o.Name() == sb.Name {
+				wagers[i] = sb.Bet
+			}
+		}
+	}
+	return wagers
+}
+
+func (s *script) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	return s.current().Insured
+}
+
+func (s *script) EarlySurrender(hand []deck.Card, dealer deck.Card) bool {
+	return s.current().EarlySurrender
+}
+
+func (s *script) Outcome(hand [][]deck.Card, dealer []deck.Card) {
+	s.round++
+	s.move = 0
+}
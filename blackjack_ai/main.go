@@ -1,19 +1,269 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/counting"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/history"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/netplay"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/sidebet"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/simulation"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/strategy"
 	"github.com/angusgmorrison/gophercises/deck"
 )
 
 func main() {
-	opts := blackjack.Options{NHands: 2}
+	seed := flag.Int64("seed", 0, "the seed to shuffle with, to replay a previous game (default random)")
+	simHands := flag.Int("simulate", 0, "simulate this many hands and report statistics instead of playing")
+	format := flag.String("format", "text", "the simulation report format: text, json or csv")
+	chartPath := flag.String("chart", "", "a CSV or YAML strategy chart to play by (default: basic strategy)")
+	check := flag.Int("check", 0, "check this many hands of the sample AI against the strategy chart")
+	count := flag.String("count", "", "count cards with this system: Hi-Lo, KO or Omega II")
+	kelly := flag.Float64("kelly", 0, "with --count and --bankroll, bet this fraction of the Kelly bet, e.g. 0.5 for half Kelly (0 bets by the count)")
+	recordPath := flag.String("record", "", "write the game's hand history to this file as JSON lines")
+	replayPath := flag.String("replay", "", "replay a hand history, checking it plays out as recorded")
+	replayAI := flag.Bool("replay-ai", false, "replay with the AI instead of the recorded moves and compare the results")
+	minBet := flag.Int("min-bet", 100, "the table minimum bet")
+	maxBet := flag.Int("max-bet", 0, "the table maximum bet (0 for no maximum)")
+	bankroll := flag.Int("bankroll", 0, "the chips the player starts with (0 for unlimited)")
+	stopLoss := flag.Int("stop-loss", 0, "leave the table after losing this many chips (0 for no limit)")
+	winGoal := flag.Int("win-goal", 0, "leave the table after winning this many chips (0 for no goal)")
+	sessionHands := flag.Int("session", 0, "simulate sessions of at most this many hands, with a fresh bankroll each")
+	serve := flag.String("serve", "", "host a table for remote players on this TCP address")
+	players := flag.Int("players", 1, "the players the hosted table waits for before dealing")
+	hands := flag.Int("hands", 100, "the hands to play at the hosted table each game")
+	connect := flag.String("connect", "", "play the AI at a table on the server at this TCP address")
+	tableName := flag.String("table", "main", "the name of the table to host or join")
+	insurance := flag.Bool("insurance", false, "offer insurance, or even money on a blackjack, against the dealer's ace")
+	sideBetSpec := flag.String("side-bets", "", "offer side bets and have the AI wager on them, e.g. perfect-pairs=5,21+3=5,lucky-ladies=5")
+	flag.Parse()
+
+	sideBets, wagers, err := parseSideBets(*sideBetSpec)
+	must(err)
+	table := blackjack.Options{
+		MinBet:    *minBet,
+		MaxBet:    *maxBet,
+		Bankroll:  *bankroll,
+		StopLoss:  *stopLoss,
+		WinGoal:   *winGoal,
+		SideBets:  sideBets,
+		Insurance: *insurance,
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			table.Seed = seed
+		}
+	})
+
+	if *serve != "" {
+		opts := table
+		opts.NHands = *hands
+		server, err := netplay.NewServer(0, netplay.TableConfig{Name: *tableName, Options: opts, Players: *players})
+		must(err)
+		must(server.ListenAndServe(*serve))
+		return
+	}
+
+	chart := strategy.ChartFor(blackjack.Options{})
+	if *chartPath != "" {
+		var err error
+		chart, err = strategy.Load(*chartPath)
+		must(err)
+	}
+
+	if *check > 0 {
+		opts := table
+		opts.NHands = *check
+		checker := strategy.NewChecker(basicAI{}, chart, opts)
+		game := blackjack.New(opts)
+		_, err := game.Play(checker)
+		must(err)
+		must(checker.Report().WriteText(os.Stdout))
+		return
+	}
+
+	newAI, err := player(chart, *count, *kelly, wagers)
+	must(err)
+
+	if *simHands > 0 {
+		must(simulate(newAI, table, *simHands, *sessionHands, *format))
+		return
+	}
+
+	if *connect != "" {
+		client := &netplay.Client{Addr: *connect, Table: *tableName, AI: newAI(table), Reconnects: 3}
+		winnings, err := client.Play()
+		must(err)
+		fmt.Println(winnings)
+		return
+	}
+
+	if *replayPath != "" {
+		if !*replayAI {
+			newAI = nil
+		}
+		must(replay(*replayPath, newAI))
+		return
+	}
+
+	opts := table
+	opts.NHands = 2
+	ai := newAI(opts)
+	var rec *history.Recorder
+	if *recordPath != "" {
+		f, err := os.Create(*recordPath)
+		must(err)
+		defer f.Close()
+		rec = history.NewRecorder(f)
+		opts = rec.Options(opts)
+		ai = rec.Seat(ai)
+	}
 	game := blackjack.New(opts)
-	winnings := game.Play(basicAI{})
+	winnings, err := game.Play(ai)
+	must(err)
+	if rec != nil {
+		must(rec.Err())
+	}
 	fmt.Println(winnings)
+	fmt.Println("seed:", game.Seed())
+}
+
+// replay replays the hand history in the file at path by the table
+// rules it was recorded with, played by an AI made by newAI if it isn't
+// nil, or else checking that the recorded moves play out as recorded.
+func replay(path string, newAI func(blackjack.Options) blackjack.AI) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rounds, err := history.Read(f)
+	if err != nil {
+		return err
+	}
+
+	if newAI == nil || len(rounds) == 0 {
+		if err := history.Check(rounds); err != nil {
+			return err
+		}
+		fmt.Printf("replayed %d rounds as recorded\n", len(rounds))
+		return nil
+	}
+	opts, err := rounds[0].Rules.Options()
+	if err != nil {
+		return err
+	}
+	replayed, err := history.Replay(rounds, newAI(opts))
+	if err != nil {
+		return err
+	}
+	fmt.Println("recorded:", net(rounds))
+	fmt.Println("replayed:", net(replayed))
+	return nil
+}
+
+// net returns the total won over the rounds.
+func net(rounds []history.Round) int {
+	total := 0
+	for _, r := range rounds {
+		total += r.Net
+	}
+	return total
+}
+
+// player returns a function creating AIs that play by the chart at a
+// table with the given rules and wager on side bets by name. If system
+// isn't empty, they count cards with the named system, betting the
+// fraction kelly of the Kelly bet if it isn't zero.
+func player(chart *strategy.Chart, system string, kelly float64, wagers map[string]int) (func(blackjack.Options) blackjack.AI, error) {
+	if system == "" {
+		return func(opts blackjack.Options) blackjack.AI {
+			return strategy.NewAI(chart, opts).WithSideBets(wagers)
+		}, nil
+	}
+	for _, s := range counting.Systems {
+		if strings.EqualFold(s.Name, system) {
+			config := strategy.CountingConfig{System: s, Kelly: kelly}
+			if s.Name == counting.HiLo.Name {
+				config.Indexes = strategy.Illustrious18
+				config.InsureAt = strategy.IllustriousInsurance
+			}
+			return func(opts blackjack.Options) blackjack.AI {
+				return strategy.NewCountingAI(chart, opts, config).WithSideBets(wagers)
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown counting system %q", system)
+}
+
+// parseSideBets parses a comma-separated list of side bets and wagers,
+// each written name=wager, returning the side bets to offer and the
+// wager on each by name.
+func parseSideBets(spec string) ([]blackjack.SideBet, map[string]int, error) {
+	if spec == "" {
+		return nil, nil, nil
+	}
+	var sideBets []blackjack.SideBet
+	wagers := make(map[string]int)
+	for _, field := range strings.Split(spec, ",") {
+		parts := strings.SplitN(field, "=", 2)
+		sb, ok := sidebet.ByName(strings.TrimSpace(parts[0]))
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown side bet %q", parts[0])
+		}
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("side bet %q has no wager", parts[0])
+		}
+		wager, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, nil, fmt.Errorf("side bet %q: %w", parts[0], err)
+		}
+		sideBets = append(sideBets, sb)
+		wagers[sb.Name()] = wager
+	}
+	return sideBets, wagers, nil
 }
 
+// simulate plays the AI for the given number of hands at the table and
+// writes a report in the given format to stdout. If the table sets
+// session rules, the hands are played in sessions of at most
+// sessionHands.
+func simulate(newAI func(blackjack.Options) blackjack.AI, opts blackjack.Options, hands, sessionHands int, format string) error {
+	report, err := simulation.Run(simulation.Config{
+		Options:      opts,
+		Hands:        hands,
+		NewAI:        func() blackjack.AI { return newAI(opts) },
+		Bankroll:     opts.Bankroll,
+		SessionHands: sessionHands,
+	})
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "text":
+		return report.WriteText(os.Stdout)
+	case "json":
+		return report.WriteJSON(os.Stdout)
+	case "csv":
+		return report.WriteCSV(os.Stdout)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+func must(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+		os.Exit(1)
+	}
+}
+
+// basicAI is a sample AI playing a few rules of thumb.
 type basicAI struct{}
 
 func (ai basicAI) Bet(shuffled bool) int {
@@ -23,6 +273,9 @@ func (ai basicAI) Bet(shuffled bool) int {
 func (ai basicAI) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
 	score := blackjack.Score(hand...)
 	if len(hand) == 2 {
+		if hand[0].Rank == deck.Ace && hand[1].Rank == deck.Ace {
+			return blackjack.MoveSplit
+		}
 		if score == 10 || score == 11 && !blackjack.Soft(hand...) {
 			return blackjack.MoveDouble
 		}
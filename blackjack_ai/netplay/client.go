@@ -0,0 +1,176 @@
+package netplay
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/sidebet"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// A Client plays a local AI at a table on a remote server, answering
+// the server's requests by asking the AI. The AI's optional interfaces
+// are honoured as they would be at a local table.
+type Client struct {
+	Addr  string // the server's TCP address
+	Table string
+	Name  string
+	AI    blackjack.AI
+	// Token rejoins the seat held by an earlier connection instead of
+	// joining Table. Play sets it once seated.
+	Token string
+	// Reconnects is the number of times Play rejoins its seat after
+	// losing the connection.
+	Reconnects int
+}
+
+// ServerError is an error reported by the server.
+type ServerError string
+
+func (e ServerError) Error() string {
+	return "netplay: server: " + string(e)
+}
+
+// Play joins the table and plays until the game is over, returning the
+// final balance. If the connection drops, it rejoins its seat up to
+// Reconnects times.
+func (c *Client) Play() (int, error) {
+	for attempt := 0; ; attempt++ {
+		balance, err := c.play()
+		var serr ServerError
+		if err == nil || errors.As(err, &serr) || c.Token == "" || attempt == c.Reconnects {
+			return balance, err
+		}
+	}
+}
+
+// play plays on a single connection to the server.
+func (c *Client) play() (int, error) {
+	nc, err := net.Dial("tcp", c.Addr)
+	if err != nil {
+		return 0, err
+	}
+	conn := newConn(nc, 0)
+	defer conn.Close()
+
+	join := Message{Type: TypeJoin, Table: c.Table, Name: c.Name, Token: c.Token}
+	if err := conn.write(join); err != nil {
+		return 0, err
+	}
+	joined := false
+	for {
+		var m Message
+		if err := conn.read(&m); err != nil {
+			return 0, fmt.Errorf("netplay: %w", err)
+		}
+		switch m.Type {
+		case TypeWelcome:
+			c.Token, joined = m.Token, true
+			continue
+		case TypeError:
+			// Once seated, errors are about late replies, which the
+			// server has already answered for the client.
+			if !joined {
+				return 0, ServerError(m.Error)
+			}
+			continue
+		case TypeOver:
+			if m.Error != "" {
+				return m.Balance, ServerError(m.Error)
+			}
+			return m.Balance, nil
+		}
+
+		reply := c.handle(m)
+		if reply.Type == "" {
+			continue
+		}
+		reply.Seq = m.Seq
+		if err := conn.write(reply); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// handle passes the message on to the AI, returning the reply to send,
+// if any.
+func (c *Client) handle(m Message) Message {
+	var upcard deck.Card
+	if m.Upcard != nil {
+		upcard = *m.Upcard
+	}
+
+	switch m.Type {
+	case TypeBet:
+		reply := Message{Type: TypeBet, Amount: c.AI.Bet(m.Shuffled)}
+		if sai, ok := c.AI.(blackjack.SideBetAI); ok && len(m.SideBets) > 0 {
+			reply.Wagers = sai.SideBets(offered(m.SideBets))
+		}
+		return reply
+	case TypeTurn:
+		return Message{Type: TypeMove, Move: blackjack.MoveName(c.AI.Play(m.Hand, upcard))}
+	case TypeInsurance:
+		yes := false
+		if iai, ok := c.AI.(blackjack.InsuranceAI); ok {
+			yes = iai.Insurance(m.Hand, upcard)
+		}
+		return Message{Type: TypeInsurance, Yes: yes}
+	case TypeSurrender:
+		yes := false
+		if sai, ok := c.AI.(blackjack.EarlySurrenderAI); ok {
+			yes = sai.EarlySurrender(m.Hand, upcard)
+		}
+		return Message{Type: TypeSurrender, Yes: yes}
+
+	case TypeDeal:
+		if w, ok := c.AI.(blackjack.WatcherAI); ok && m.Card != nil {
+			w.Seen(*m.Card)
+		}
+	case TypeRejected:
+		if rai, ok := c.AI.(blackjack.RetryAI); ok {
+			rai.Rejected(errors.New(m.Error))
+		}
+	case TypeOutcome:
+		if m.Result == nil {
+			break
+		}
+		r := m.Result.result()
+		hands := make([][]deck.Card, len(r.Hands))
+		for i, h := range r.Hands {
+			hands[i] = h.Cards
+		}
+		c.AI.Outcome(hands, r.Dealer)
+		if rai, ok := c.AI.(blackjack.ResultAI); ok {
+			rai.Result(r)
+		}
+	}
+	return Message{}
+}
+
+// offered returns the side bets with the names, as offered by the
+// server.
+func offered(names []string) []blackjack.SideBet {
+	sideBets := make([]blackjack.SideBet, len(names))
+	for i, name := range names {
+		sb, ok := sidebet.ByName(name)
+		if !ok {
+			sb = remoteSideBet(name)
+		}
+		sideBets[i] = sb
+	}
+	return sideBets
+}
+
+// remoteSideBet is a side bet offered by the server that the client
+// doesn't know. The server settles it, so it never pays locally.
+type remoteSideBet string
+
+func (sb remoteSideBet) Name() string {
+	return string(sb)
+}
+
+func (sb remoteSideBet) Pays(hand []deck.Card, dealer []deck.Card) int {
+	return 0
+}
@@ -0,0 +1,249 @@
+package netplay
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/counting"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/sidebet"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/strategy"
+)
+
+// serve starts a server hosting the tables, returning its address.
+func serve(t *testing.T, timeout time.Duration, tables ...TableConfig) string {
+	t.Helper()
+	s, err := NewServer(timeout, tables...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go s.Serve(l)
+	return l.Addr().String()
+}
+
+// result is the outcome of a Client's game.
+type result struct {
+	balance int
+	err     error
+}
+
+func play(c *Client) <-chan result {
+	done := make(chan result, 1)
+	go func() {
+		balance, err := c.Play()
+		done <- result{balance, err}
+	}()
+	return done
+}
+
+func TestRemoteTable(t *testing.T) {
+	// Remote players must play exactly as they would at a local table.
+	opts := blackjack.Options{NHands: 200, Seed: blackjack.Seed(1), Insurance: true, LateSurrender: true, SideBets: sidebet.All}
+	chart := strategy.ChartFor(opts)
+	wagers := map[string]int{"perfect-pairs": 10, "lucky-ladies": 5}
+	g := blackjack.New(opts)
+	want, err := g.PlayTable(
+		strategy.NewAI(chart, opts).WithSideBets(wagers),
+		strategy.NewCountingAI(chart, opts, strategy.CountingConfig{System: counting.HiLo}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := serve(t, time.Second, TableConfig{Name: "main", Options: opts, Players: 2})
+	first := play(&Client{Addr: addr, Table: "main", AI: strategy.NewAI(chart, opts).WithSideBets(wagers)})
+	// Seats are taken in the order players join.
+	time.Sleep(50 * time.Millisecond)
+	second := play(&Client{Addr: addr, Table: "main", AI: strategy.NewCountingAI(chart, opts, strategy.CountingConfig{System: counting.HiLo})})
+
+	for i, done := range []<-chan result{first, second} {
+		r := <-done
+		if r.err != nil {
+			t.Fatalf("seat %d: %v", i+1, r.err)
+		}
+		if r.balance != want[i] {
+			t.Errorf("seat %d finished with %d, want %d as at a local table", i+1, r.balance, want[i])
+		}
+	}
+}
+
+func TestTurnTimeout(t *testing.T) {
+	// A player who never answers bets the minimum and stands on every
+	// hand.
+	addr := serve(t, 20*time.Millisecond, TableConfig{Name: "main", Options: blackjack.Options{NHands: 3}})
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	json.NewEncoder(c).Encode(Message{Type: TypeJoin, Table: "main"})
+
+	dec := json.NewDecoder(c)
+	bets := 0
+	for {
+		var m Message
+		if err := dec.Decode(&m); err != nil {
+			t.Fatal(err)
+		}
+		switch m.Type {
+		case TypeBet:
+			bets++
+		case TypeTurn:
+			if len(m.Hand) != 2 {
+				t.Errorf("hand %v was hit after timing out", m.Hand)
+			}
+		case TypeOutcome:
+			if m.Result.Hands[0].Bet != 100 {
+				t.Errorf("bet %d after timing out, want the table minimum", m.Result.Hands[0].Bet)
+			}
+		case TypeOver:
+			if bets != 3 {
+				t.Errorf("asked to bet %d times, want 3", bets)
+			}
+			return
+		}
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	opts := blackjack.Options{NHands: 20, Seed: blackjack.Seed(2)}
+	addr := serve(t, 5*time.Second, TableConfig{Name: "main", Options: opts})
+
+	// The first connection drops as soon as it's seated.
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.NewEncoder(c).Encode(Message{Type: TypeJoin, Table: "main"})
+	var welcome Message
+	if err := json.NewDecoder(c).Decode(&welcome); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	chart := strategy.ChartFor(opts)
+	client := &Client{Addr: addr, Token: welcome.Token, AI: strategy.NewAI(chart, opts)}
+	select {
+	case r := <-play(client):
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+		g := blackjack.New(opts)
+		if want, _ := g.Play(strategy.NewAI(chart, opts)); r.balance != want {
+			t.Errorf("finished with %d, want %d", r.balance, want)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("game stalled after reconnecting")
+	}
+}
+
+func TestJoinErrors(t *testing.T) {
+	addr := serve(t, time.Second, TableConfig{Name: "main"})
+	testCases := []*Client{
+		{Addr: addr, Table: "missing", AI: strategy.NewAI(strategy.S17(), blackjack.Options{})},
+		{Addr: addr, Token: "stale", AI: strategy.NewAI(strategy.S17(), blackjack.Options{})},
+	}
+	for _, c := range testCases {
+		if _, err := c.Play(); err == nil {
+			t.Errorf("joining %+v: expected an error", c)
+		} else if _, ok := err.(ServerError); !ok {
+			t.Errorf("joining %+v: got %v, want a ServerError", c, err)
+		}
+	}
+
+	if _, err := NewServer(0, TableConfig{Name: "a"}, TableConfig{Name: "a"}); err == nil {
+		t.Error("expected an error configuring a table twice")
+	}
+	if _, err := NewServer(0, TableConfig{Name: "a", Players: blackjack.MaxSeats + 1}); err == nil {
+		t.Error("expected an error configuring too many players")
+	}
+}
+
+func TestInvalidReplies(t *testing.T) {
+	// A player whose bets are too big and whose moves are unknown is
+	// rejected until the game bets the minimum and stands for them,
+	// while the rest of the table plays on.
+	opts := blackjack.Options{NHands: 5, MaxBet: 500}
+	addr := serve(t, time.Second, TableConfig{Name: "main", Options: opts, Players: 2})
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	enc, dec := json.NewEncoder(c), json.NewDecoder(c)
+	enc.Encode(Message{Type: TypeJoin, Table: "main"})
+	good := play(&Client{Addr: addr, Table: "main", AI: strategy.NewAI(strategy.ChartFor(opts), opts)})
+
+	rejected := 0
+	for {
+		var m Message
+		if err := dec.Decode(&m); err != nil {
+			t.Fatal(err)
+		}
+		switch m.Type {
+		case TypeBet:
+			enc.Encode(Message{Type: TypeBet, Seq: m.Seq, Amount: 1000000})
+		case TypeTurn:
+			enc.Encode(Message{Type: TypeMove, Seq: m.Seq, Move: "fold"})
+		case TypeRejected:
+			rejected++
+		case TypeOutcome:
+			if bet := m.Result.Hands[0].Bet; bet != 100 {
+				t.Errorf("bet %d after invalid bets, want the table minimum", bet)
+			}
+		case TypeOver:
+			if m.Error != "" {
+				t.Errorf("game over with error %q", m.Error)
+			}
+			if rejected == 0 {
+				t.Error("no bets or moves rejected")
+			}
+			if r := <-good; r.err != nil {
+				t.Errorf("the other seat: %v", r.err)
+			}
+			return
+		}
+	}
+}
+
+func TestLeaveBeforeStart(t *testing.T) {
+	// A player who disconnects before the table fills gives up their
+	// seat to the next to join.
+	opts := blackjack.Options{NHands: 5}
+	addr := serve(t, 5*time.Second, TableConfig{Name: "main", Options: opts, Players: 2})
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.NewEncoder(c).Encode(Message{Type: TypeJoin, Table: "main"})
+	var welcome Message
+	if err := json.NewDecoder(c).Decode(&welcome); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+	time.Sleep(50 * time.Millisecond)
+
+	chart := strategy.ChartFor(opts)
+	first := play(&Client{Addr: addr, Table: "main", AI: strategy.NewAI(chart, opts)})
+	second := play(&Client{Addr: addr, Table: "main", AI: strategy.NewAI(chart, opts)})
+	for i, done := range []<-chan result{first, second} {
+		select {
+		case r := <-done:
+			if r.err != nil {
+				t.Errorf("player %d: %v", i+1, r.err)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("player %d: game stalled waiting on a player who left", i+1)
+		}
+	}
+	if _, err := (&Client{Addr: addr, Token: welcome.Token, AI: strategy.NewAI(chart, opts)}).Play(); err == nil {
+		t.Error("rejoined a released seat")
+	}
+}
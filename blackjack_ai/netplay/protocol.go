@@ -0,0 +1,176 @@
+// Package netplay hosts blackjack tables on a TCP server for remote
+// players to join, and adapts any local blackjack.AI into a remote
+// player, so that bots can compete over the network.
+//
+// # Protocol
+//
+// The client and server exchange JSON messages, one per line, each
+// named by its "type". A client opens by joining a table:
+//
+//	{"type":"join","table":"main","name":"bot"}
+//
+// and the server replies with the client's seat, counted from 1, and a
+// token:
+//
+//	{"type":"welcome","table":"main","seat":1,"token":"4f1c..."}
+//
+// A client that loses its connection rejoins its seat by sending the
+// token in place of the table:
+//
+//	{"type":"join","token":"4f1c..."}
+//
+// The game begins once the table fills. The server then sends requests,
+// each numbered by "seq", which the client answers with a reply of the
+// same seq:
+//
+//	bet        {"shuffled":true,"min_bet":100}       → bet {"amount":100}
+//	turn       {"hand":["10S","6H"],"upcard":"7D"}   → move {"move":"hit"}
+//	insurance  {"hand":["10S","6H"],"upcard":"AD"}   → insurance {"yes":false}
+//	surrender  {"hand":["10S","6H"],"upcard":"10D"}  → surrender {"yes":false}
+//
+// At tables that offer side bets, the bet request names them in
+// "side_bets", and the reply may wager on each, in order, in "wagers":
+//
+//	bet        {"min_bet":100,"side_bets":["21+3"]}  → bet {"amount":100,"wagers":[5]}
+//
+// Moves are "hit", "stand", "double", "split" and "surrender". Only
+// tables that offer them send insurance and surrender, which is early
+// surrender, requests. The server also sends notifications, which need
+// no reply:
+//
+//	deal      {"card":"QS"}          a card turned face up at any seat
+//	rejected  {"error":"..."}        the last bet or move broke the rules, and will be asked for again
+//	outcome   {"result":{...}}       the settlement of the client's bets for the round
+//	over      {"balance":-200}       the game is over; "error" is set if it ended early
+//	error     {"error":"..."}        the client's message couldn't be accepted
+//
+// A request left unanswered for the server's turn timeout is answered
+// for the client: it bets the table minimum, stands, and declines
+// insurance and surrender, as are bets and moves the table rejects too
+// many times. A client that disconnects before the game starts gives up
+// its seat; once the game is under way, its seat is held until the game
+// is over.
+package netplay
+
+import (
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// Message types.
+const (
+	TypeJoin      = "join"
+	TypeWelcome   = "welcome"
+	TypeBet       = "bet"
+	TypeTurn      = "turn"
+	TypeMove      = "move"
+	TypeInsurance = "insurance"
+	TypeSurrender = "surrender"
+	TypeDeal      = "deal"
+	TypeRejected  = "rejected"
+	TypeOutcome   = "outcome"
+	TypeOver      = "over"
+	TypeError     = "error"
+)
+
+// replyTypes maps the type of each request to the type of its reply.
+var replyTypes = map[string]string{
+	TypeBet:       TypeBet,
+	TypeTurn:      TypeMove,
+	TypeInsurance: TypeInsurance,
+	TypeSurrender: TypeSurrender,
+}
+
+// Message is a message of any type. Only the fields the type uses are
+// set.
+type Message struct {
+	Type string `json:"type"`
+	Seq  int    `json:"seq,omitempty"` // numbers a request, and is repeated in its reply
+
+	Table string `json:"table,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Token string `json:"token,omitempty"`
+	Seat  int    `json:"seat,omitempty"`
+
+	Shuffled bool        `json:"shuffled,omitempty"`
+	MinBet   int         `json:"min_bet,omitempty"`
+	MaxBet   int         `json:"max_bet,omitempty"`
+	Amount   int         `json:"amount,omitempty"`
+	SideBets []string    `json:"side_bets,omitempty"` // the names of the side bets offered
+	Wagers   []int       `json:"wagers,omitempty"`    // the wager on each side bet offered
+	Hand     []deck.Card `json:"hand,omitempty"`
+	Upcard   *deck.Card  `json:"upcard,omitempty"`
+	Card     *deck.Card  `json:"card,omitempty"`
+	Move     string      `json:"move,omitempty"`
+	Yes      bool        `json:"yes,omitempty"`
+
+	Result  *Result `json:"result,omitempty"`
+	Balance int     `json:"balance,omitempty"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Result is the settlement of a player's bets at the end of a round.
+type Result struct {
+	Hands     []Hand      `json:"hands"`
+	Dealer    []deck.Card `json:"dealer"`
+	Insurance int         `json:"insurance,omitempty"` // the amount won on insurance
+	SideBets  []SideBet   `json:"side_bets,omitempty"`
+	Net       int         `json:"net"`
+}
+
+// Hand is the settlement of one of a player's hands.
+type Hand struct {
+	Cards    []deck.Card `json:"cards"`
+	Bet      int         `json:"bet"`
+	Winnings int         `json:"winnings"`
+	Outcome  string      `json:"outcome"` // "Lost", "Pushed", "Won", "Natural" or "Surrendered"
+}
+
+// SideBet is the settlement of one of a player's side bets.
+type SideBet struct {
+	Name     string `json:"name"`
+	Bet      int    `json:"bet"`
+	Winnings int    `json:"winnings"`
+}
+
+func newResult(r blackjack.Result) *Result {
+	res := &Result{Dealer: r.Dealer, Insurance: r.Insurance, Net: r.Net}
+	for _, h := range r.Hands {
+		res.Hands = append(res.Hands, Hand{
+			Cards:    h.Cards,
+			Bet:      h.Bet,
+			Winnings: h.Winnings,
+			Outcome:  h.Outcome.String(),
+		})
+	}
+	for _, sb := range r.SideBets {
+		res.SideBets = append(res.SideBets, SideBet(sb))
+	}
+	return res
+}
+
+// result converts the result back to the engine's.
+func (r *Result) result() blackjack.Result {
+	res := blackjack.Result{Dealer: r.Dealer, Insurance: r.Insurance, Net: r.Net}
+	for _, h := range r.Hands {
+		res.Hands = append(res.Hands, blackjack.HandResult{
+			Cards:    h.Cards,
+			Bet:      h.Bet,
+			Winnings: h.Winnings,
+			Outcome:  parseOutcome(h.Outcome),
+		})
+	}
+	for _, sb := range r.SideBets {
+		res.SideBets = append(res.SideBets, blackjack.SideBetResult(sb))
+	}
+	return res
+}
+
+func parseOutcome(s string) blackjack.Outcome {
+	for o := blackjack.Lost; o <= blackjack.Surrendered; o++ {
+		if o.String() == s {
+			return o
+		}
+	}
+	return blackjack.Lost
+}
@@ -0,0 +1,470 @@
+package netplay
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// DefaultTurnTimeout is the time a remote player has to answer each
+// request unless the Server sets its own.
+const DefaultTurnTimeout = 30 * time.Second
+
+// TableConfig describes a table hosted by a Server.
+type TableConfig struct {
+	Name    string
+	Options blackjack.Options // the game played at the table each time it fills
+	Players int               // the players the table waits for before dealing; defaults to 1
+}
+
+// A Server hosts blackjack tables for remote players. Each time a table
+// fills, it plays a game, after which the table is cleared for new
+// players.
+type Server struct {
+	turnTimeout time.Duration
+
+	mu      sync.Mutex
+	tables  map[string]*table
+	players map[string]*player // by token
+}
+
+// table is a table hosted by the server.
+type table struct {
+	config  TableConfig
+	players []*player // the players seated for the next or current game
+	playing bool
+}
+
+// NewServer returns a server hosting the tables, giving players
+// turnTimeout to answer each request, or DefaultTurnTimeout if it is 0.
+func NewServer(turnTimeout time.Duration, tables ...TableConfig) (*Server, error) {
+	if turnTimeout == 0 {
+		turnTimeout = DefaultTurnTimeout
+	}
+	s := &Server{
+		turnTimeout: turnTimeout,
+		tables:      make(map[string]*table),
+		players:     make(map[string]*player),
+	}
+	for _, tc := range tables {
+		if tc.Players == 0 {
+			tc.Players = 1
+		}
+		if tc.Players < 0 || tc.Players > blackjack.MaxSeats {
+			return nil, fmt.Errorf("netplay: table %q: a table seats 1 to %d players", tc.Name, blackjack.MaxSeats)
+		}
+		if _, ok := s.tables[tc.Name]; ok {
+			return nil, fmt.Errorf("netplay: table %q is configured twice", tc.Name)
+		}
+		s.tables[tc.Name] = &table{config: tc}
+	}
+	return s, nil
+}
+
+// ListenAndServe listens on the TCP address and serves players.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve serves players connecting to the listener, returning when it
+// fails to accept a connection, as when it is closed.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handle(c)
+	}
+}
+
+// handle seats the player joining on the connection, then passes their
+// replies on until they disconnect. A player who disconnects before the
+// game starts gives up their seat.
+func (s *Server) handle(c net.Conn) {
+	conn := newConn(c, s.turnTimeout)
+	defer conn.Close()
+
+	var m Message
+	if err := conn.read(&m); err != nil {
+		return
+	}
+	if m.Type != TypeJoin {
+		conn.write(Message{Type: TypeError, Error: "expected a join message"})
+		return
+	}
+	p, err := s.join(m)
+	if err != nil {
+		conn.write(Message{Type: TypeError, Error: err.Error()})
+		return
+	}
+
+	p.attach(conn)
+	defer s.leave(p, conn)
+	for {
+		var m Message
+		if err := conn.read(&m); err != nil {
+			return
+		}
+		if err := p.reply(m); err != nil {
+			p.send(Message{Type: TypeError, Seq: m.Seq, Error: err.Error()})
+		}
+	}
+}
+
+// join seats a player at the table named in the join message, or
+// returns the player holding the seat of the token.
+func (s *Server) join(m Message) (*player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m.Token != "" {
+		p, ok := s.players[m.Token]
+		if !ok {
+			return nil, errors.New("unknown token; the game may be over")
+		}
+		return p, nil
+	}
+
+	t, ok := s.tables[m.Table]
+	if !ok {
+		return nil, fmt.Errorf("no table %q", m.Table)
+	}
+	if t.playing {
+		return nil, fmt.Errorf("table %q is in play", m.Table)
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	p := newPlayer(t, len(t.players), token, s.turnTimeout)
+	t.players = append(t.players, p)
+	s.players[token] = p
+	if len(t.players) == t.config.Players {
+		t.playing = true
+		go s.play(t)
+	}
+	return p, nil
+}
+
+// leave disconnects the player from conn. If their table's game hasn't
+// started, their seat is released and the players after them move up,
+// so that the game isn't held up waiting on them.
+func (s *Server) leave(p *player, conn *conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := p.table
+	if !p.detach(conn) || t.playing || s.players[p.token] != p {
+		return
+	}
+	delete(s.players, p.token)
+	players := make([]*player, 0, len(t.players)-1)
+	for _, q := range t.players {
+		if q != p {
+			players = append(players, q)
+		}
+	}
+	t.players = players
+	for i, q := range t.players {
+		q.reseat(i)
+	}
+}
+
+// play plays a game at the full table, then clears it.
+func (s *Server) play(t *table) {
+	ais := make([]blackjack.AI, len(t.players))
+	for i, p := range t.players {
+		ais[i] = p
+	}
+	g := blackjack.New(t.config.Options)
+	balances, err := g.PlayTable(ais...)
+
+	s.mu.Lock()
+	players := t.players
+	t.players, t.playing = nil, false
+	for _, p := range players {
+		delete(s.players, p.token)
+	}
+	s.mu.Unlock()
+
+	for i, p := range players {
+		over := Message{Type: TypeOver}
+		if i < len(balances) {
+			over.Balance = balances[i]
+		}
+		if err != nil {
+			over.Error = err.Error()
+		}
+		p.send(over)
+		p.close()
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// conn is a connection carrying messages as lines of JSON.
+type conn struct {
+	net.Conn
+	dec     *json.Decoder
+	enc     *json.Encoder
+	timeout time.Duration // the time allowed for each write
+}
+
+func newConn(c net.Conn, timeout time.Duration) *conn {
+	return &conn{Conn: c, dec: json.NewDecoder(c), enc: json.NewEncoder(c), timeout: timeout}
+}
+
+func (c *conn) read(m *Message) error {
+	return c.dec.Decode(m)
+}
+
+func (c *conn) write(m Message) error {
+	if c.timeout > 0 {
+		c.SetWriteDeadline(time.Now().Add(c.timeout))
+	}
+	return c.enc.Encode(m)
+}
+
+// player is a remote player, who plays a seat at a table as a
+// blackjack.AI by answering the server's requests. Their connection may
+// drop and be replaced while the game goes on.
+type player struct {
+	table   *table
+	seat    int
+	token   string
+	timeout time.Duration
+
+	mu      sync.Mutex
+	conn    *conn    // nil while the player is disconnected
+	pending *Message // the request awaiting a reply, if any
+	seq     int
+	replies chan Message
+	wagers  []int // the side bets placed with the last bet
+}
+
+func newPlayer(t *table, seat int, token string, timeout time.Duration) *player {
+	return &player{
+		table:   t,
+		seat:    seat,
+		token:   token,
+		timeout: timeout,
+		replies: make(chan Message, 1),
+	}
+}
+
+// attach connects the player on conn, welcoming them to their seat and
+// repeating any request they have yet to answer.
+func (p *player) attach(conn *conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.conn = conn
+	p.welcome()
+	if p.pending != nil {
+		p.write(*p.pending)
+	}
+}
+
+// welcome tells the player the seat they hold. The caller must hold
+// p.mu.
+func (p *player) welcome() {
+	p.write(Message{
+		Type:  TypeWelcome,
+		Table: p.table.config.Name,
+		Seat:  p.seat + 1,
+		Token: p.token,
+	})
+}
+
+// reseat moves the player to another seat before the game starts,
+// welcoming them to it if it changed.
+func (p *player) reseat(seat int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.seat != seat {
+		p.seat = seat
+		p.welcome()
+	}
+}
+
+// detach disconnects the player if they are still connected on conn,
+// reporting whether they are left disconnected.
+func (p *player) detach(conn *conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == conn {
+		p.conn = nil
+	}
+	return p.conn == nil
+}
+
+// close disconnects the player at the end of the game.
+func (p *player) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+}
+
+// send sends the message to the player if they are connected.
+func (p *player) send(m Message) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.write(m)
+}
+
+// write sends the message to the player if they are connected,
+// disconnecting them if it fails. The caller must hold p.mu.
+func (p *player) write(m Message) {
+	if p.conn == nil {
+		return
+	}
+	if err := p.conn.write(m); err != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+}
+
+// request sends the request to the player and waits for their reply,
+// returning false if they don't reply in time.
+func (p *player) request(m Message) (Message, bool) {
+	p.mu.Lock()
+	p.seq++
+	m.Seq = p.seq
+	p.pending = &m
+	p.write(m)
+	p.mu.Unlock()
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+	select {
+	case r := <-p.replies:
+		return r, true
+	case <-timer.C:
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending == nil {
+		// The reply arrived as the request timed out.
+		return <-p.replies, true
+	}
+	p.pending = nil
+	return Message{}, false
+}
+
+// reply passes on the player's reply to the pending request.
+func (p *player) reply(m Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending == nil || m.Seq != p.pending.Seq {
+		return fmt.Errorf("no request %d awaiting a reply", m.Seq)
+	}
+	if want := replyTypes[p.pending.Type]; m.Type != want {
+		return fmt.Errorf("expected a %s reply to request %d", want, m.Seq)
+	}
+	p.pending = nil
+	p.replies <- m
+	return nil
+}
+
+// Bet asks the player for their bet and side bets. The game rejects a
+// bet it doesn't allow, as it would any AI's, asking again until the
+// player bets the table minimum after too many attempts. A player who
+// doesn't reply in time bets the minimum straight away.
+func (p *player) Bet(shuffled bool) int {
+	opts := p.table.config.Options.WithDefaults()
+	var sideBets []string
+	for _, sb := range opts.SideBets {
+		sideBets = append(sideBets, sb.Name())
+	}
+	r, ok := p.request(Message{
+		Type:     TypeBet,
+		Shuffled: shuffled,
+		MinBet:   opts.MinBet,
+		MaxBet:   opts.MaxBet,
+		SideBets: sideBets,
+	})
+	p.wagers = r.Wagers
+	if !ok {
+		return opts.MinBet
+	}
+	return r.Amount
+}
+
+// SideBets returns the side bets the player placed with their bet.
+func (p *player) SideBets(offered []blackjack.SideBet) []int {
+	return p.wagers
+}
+
+// Play asks the player for their move. A move the game doesn't allow,
+// or doesn't know, is rejected and asked for again, until the hand is
+// stood on after too many attempts. A player who doesn't reply in time
+// stands straight away.
+func (p *player) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	r, ok := p.request(Message{Type: TypeTurn, Hand: hand, Upcard: &dealer})
+	if !ok {
+		return blackjack.MoveStand
+	}
+	if move := blackjack.ParseMove(r.Move); move != nil {
+		return move
+	}
+	return unknownMove(r.Move)
+}
+
+// unknownMove returns a move the game rejects as invalid, explaining
+// that the player's move isn't one it knows.
+func unknownMove(name string) blackjack.Move {
+	return func(*blackjack.Game) error {
+		return fmt.Errorf("%w: unknown move %q", blackjack.ErrInvalidMove, name)
+	}
+}
+
+func (p *player) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	r, _ := p.request(Message{Type: TypeInsurance, Hand: hand, Upcard: &dealer})
+	return r.Yes
+}
+
+func (p *player) EarlySurrender(hand []deck.Card, dealer deck.Card) bool {
+	r, _ := p.request(Message{Type: TypeSurrender, Hand: hand, Upcard: &dealer})
+	return r.Yes
+}
+
+func (p *player) Seen(card deck.Card) {
+	p.send(Message{Type: TypeDeal, Card: &card})
+}
+
+func (p *player) Rejected(err error) {
+	p.send(Message{Type: TypeRejected, Error: err.Error()})
+}
+
+func (p *player) Result(r blackjack.Result) {
+	p.send(Message{Type: TypeOutcome, Result: newResult(r)})
+}
+
+func (p *player) Outcome(hand [][]deck.Card, dealer []deck.Card) {
+	// noop: the player is sent the Result
+}
@@ -0,0 +1,131 @@
+// Package sidebet implements side bets commonly offered at blackjack
+// tables, which are settled on the player's first two cards and the
+// dealer's hand.
+package sidebet
+
+import (
+	"strings"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// All are the side bets implemented by the package.
+var All = []blackjack.SideBet{PerfectPairs{}, TwentyOnePlusThree{}, LuckyLadies{}}
+
+// ByName returns the side bet with the name, ignoring case.
+func ByName(name string) (blackjack.SideBet, bool) {
+	for _, sb := range All {
+		if strings.EqualFold(sb.Name(), name) {
+			return sb, true
+		}
+	}
+	return nil, false
+}
+
+// PerfectPairs pays when the player's first two cards are a pair: 25 to
+// 1 for a perfect pair of the same suit, 12 to 1 for a coloured pair of
+// the same colour and 6 to 1 for a mixed pair.
+type PerfectPairs struct{}
+
+func (PerfectPairs) Name() string {
+	return "perfect-pairs"
+}
+
+func (PerfectPairs) Pays(hand []deck.Card, dealer []deck.Card) int {
+	a, b := hand[0], hand[1]
+	switch {
+	case a.Rank != b.Rank:
+		return 0
+	case a.Suit == b.Suit:
+		return 25
+	case red(a) == red(b):
+		return 12
+	default:
+		return 6
+	}
+}
+
+func red(c deck.Card) bool {
+	return c.Suit == deck.Hearts || c.Suit == deck.Diamonds
+}
+
+// TwentyOnePlusThree pays on the three-card poker hand made by the
+// player's first two cards and the dealer's upcard: 100 to 1 for suited
+// trips, 40 to 1 for a straight flush, 30 to 1 for three of a kind, 10
+// to 1 for a straight and 5 to 1 for a flush. Aces play high or low in
+// straights.
+type TwentyOnePlusThree struct{}
+
+func (TwentyOnePlusThree) Name() string {
+	return "21+3"
+}
+
+func (TwentyOnePlusThree) Pays(hand []deck.Card, dealer []deck.Card) int {
+	cards := []deck.Card{hand[0], hand[1], dealer[0]}
+	flush := cards[0].Suit == cards[1].Suit && cards[1].Suit == cards[2].Suit
+	trips := cards[0].Rank == cards[1].Rank && cards[1].Rank == cards[2].Rank
+	switch {
+	case trips && flush:
+		return 100
+	case straight(cards) && flush:
+		return 40
+	case trips:
+		return 30
+	case straight(cards):
+		return 10
+	case flush:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// straight reports whether three cards are of consecutive ranks, with
+// aces either below twos or above kings.
+func straight(cards []deck.Card) bool {
+	var ranks [deck.King + 2]bool // an ace is also counted at King+1
+	for _, c := range cards {
+		if ranks[c.Rank] {
+			return false
+		}
+		ranks[c.Rank] = true
+	}
+	ranks[deck.King+1] = ranks[deck.Ace]
+	for r := deck.Ace; r+2 <= deck.King+1; r++ {
+		if ranks[r] && ranks[r+1] && ranks[r+2] {
+			return true
+		}
+	}
+	return false
+}
+
+// LuckyLadies pays when the player's first two cards total 20: 1000 to
+// 1 for a pair of queens of hearts against a dealer blackjack, 125 to 1
+// for a pair of queens of hearts, 19 to 1 for a matched 20 of the same
+// rank and suit, 9 to 1 for a suited 20 and 4 to 1 for any other 20.
+type LuckyLadies struct{}
+
+func (LuckyLadies) Name() string {
+	return "lucky-ladies"
+}
+
+func (LuckyLadies) Pays(hand []deck.Card, dealer []deck.Card) int {
+	a, b := hand[0], hand[1]
+	matched := a.Rank == b.Rank && a.Suit == b.Suit
+	ladies := matched && a.Rank == deck.Queen && a.Suit == deck.Hearts
+	switch {
+	case blackjack.Score(a, b) != 20:
+		return 0
+	case ladies && blackjack.Blackjack(dealer...):
+		return 1000
+	case ladies:
+		return 125
+	case matched:
+		return 19
+	case a.Suit == b.Suit:
+		return 9
+	default:
+		return 4
+	}
+}
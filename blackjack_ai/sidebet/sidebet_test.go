@@ -0,0 +1,68 @@
+package sidebet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+func cards(t *testing.T, s string) []deck.Card {
+	t.Helper()
+	var cards []deck.Card
+	for _, f := range strings.Fields(s) {
+		c, err := deck.ParseCard(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cards = append(cards, c)
+	}
+	return cards
+}
+
+func TestPays(t *testing.T) {
+	testCases := []struct {
+		sideBet      blackjack.SideBet
+		hand, dealer string
+		want         int
+	}{
+		{PerfectPairs{}, "8S 8S", "2C", 25},
+		{PerfectPairs{}, "8H 8D", "2C", 12},
+		{PerfectPairs{}, "8H 8S", "2C", 6},
+		{PerfectPairs{}, "8H 9H", "2C", 0},
+
+		{TwentyOnePlusThree{}, "7D 7D", "7D", 100},
+		{TwentyOnePlusThree{}, "QC KC", "AC", 40},
+		{TwentyOnePlusThree{}, "7D 7S", "7H", 30},
+		{TwentyOnePlusThree{}, "2D AS", "3H", 10},
+		{TwentyOnePlusThree{}, "KD AS", "2H", 0},
+		{TwentyOnePlusThree{}, "2H 9H", "KH", 5},
+		{TwentyOnePlusThree{}, "2H 9H", "KS", 0},
+
+		{LuckyLadies{}, "QH QH", "AS KD", 1000},
+		{LuckyLadies{}, "QH QH", "AS 9D", 125},
+		{LuckyLadies{}, "JS JS", "AS KD", 19},
+		{LuckyLadies{}, "KS 10S", "5C 9D", 9},
+		{LuckyLadies{}, "AS 9D", "5C 9D", 4},
+		{LuckyLadies{}, "KS 9S", "5C 9D", 0},
+	}
+	for _, tc := range testCases {
+		got := tc.sideBet.Pays(cards(t, tc.hand), cards(t, tc.dealer))
+		if got != tc.want {
+			t.Errorf("%s on %s against %s pays %d, want %d", tc.sideBet.Name(), tc.hand, tc.dealer, got, tc.want)
+		}
+	}
+}
+
+func TestByName(t *testing.T) {
+	for _, sb := range All {
+		got, ok := ByName(strings.ToUpper(sb.Name()))
+		if !ok || got != sb {
+			t.Errorf("ByName(%q) = %v, %t", sb.Name(), got, ok)
+		}
+	}
+	if _, ok := ByName("insurance"); ok {
+		t.Error("found a side bet that doesn't exist")
+	}
+}
@@ -0,0 +1,224 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Report summarises the results of a simulation. Money is measured in
+// chips, and every figure is per round: a round is a single opening bet,
+// however many hands it is split into. Side bets are reported in
+// SideBets alone, and left out of every other figure.
+type Report struct {
+	Seed   int64 `json:"seed"`
+	Rounds int64 `json:"rounds"`
+
+	Wagered int64 `json:"wagered"` // the total of the opening bets
+	Net     int64 `json:"net"`
+
+	EV     float64     `json:"ev"`      // the mean amount won per round
+	EVLow  float64     `json:"ev_low"`  // the lower bound of the 95% confidence interval of EV
+	EVHigh float64     `json:"ev_high"` // the upper bound of the 95% confidence interval of EV
+	Edge   float64     `json:"edge"`    // the amount won per chip of opening bet
+	StdDev float64     `json:"std_dev"` // the standard deviation of the amount won per round
+	Ruin   *float64    `json:"risk_of_ruin,omitempty"`
+	Freq   Frequencies `json:"frequencies"`
+	// Sessions counts how the sessions ended, if the player had session
+	// rules.
+	Sessions *Sessions `json:"sessions,omitempty"`
+	// SideBets are the results of each side bet placed, in the order the
+	// table offers them.
+	SideBets []SideBet `json:"side_bets,omitempty"`
+}
+
+// SideBet summarises the results of a side bet.
+type SideBet struct {
+	Name    string  `json:"name"`
+	Placed  int64   `json:"placed"` // the rounds it was placed in
+	Wagered int64   `json:"wagered"`
+	Net     int64   `json:"net"`
+	Edge    float64 `json:"edge"`     // the amount won per chip wagered
+	HitRate float64 `json:"hit_rate"` // the fraction of wagers that won
+}
+
+// Sessions counts the sessions played and how they ended. Sessions that
+// ran out of hands before their rules ended them are counted in Count
+// alone.
+type Sessions struct {
+	Count    int64 `json:"count"`
+	Bust     int64 `json:"bust"`      // sessions that lost the bankroll
+	StopLoss int64 `json:"stop_loss"` // sessions that hit the stop-loss
+	WinGoal  int64 `json:"win_goal"`  // sessions that reached the win goal
+}
+
+// BustRate returns the fraction of sessions that lost the bankroll.
+func (s Sessions) BustRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Bust) / float64(s.Count)
+}
+
+// Frequencies are the fractions of rounds settled in each way. Rounds
+// with a blackjack or a surrender are counted as such; other rounds are
+// wins, losses or pushes according to their net result.
+type Frequencies struct {
+	Win       float64 `json:"win"`
+	Loss      float64 `json:"loss"`
+	Push      float64 `json:"push"`
+	Blackjack float64 `json:"blackjack"`
+	Surrender float64 `json:"surrender"`
+}
+
+func newReport(cfg Config, t tally) Report {
+	n := float64(t.rounds)
+	r := Report{
+		Seed:    *cfg.Options.Seed,
+		Rounds:  t.rounds,
+		Wagered: t.wagered,
+		Net:     t.net,
+	}
+	if t.rounds == 0 {
+		return r
+	}
+
+	r.EV = float64(t.net) / n
+	if t.rounds > 1 {
+		variance := (t.sumSquares - n*r.EV*r.EV) / (n - 1)
+		r.StdDev = math.Sqrt(math.Max(variance, 0))
+	}
+	margin := z95 * r.StdDev / math.Sqrt(n)
+	r.EVLow, r.EVHigh = r.EV-margin, r.EV+margin
+	if t.wagered != 0 {
+		r.Edge = float64(t.net) / float64(t.wagered)
+	}
+	if cfg.Bankroll > 0 {
+		ruin := riskOfRuin(cfg.Bankroll, r.EV, r.StdDev)
+		r.Ruin = &ruin
+	}
+	if t.sessions > 0 {
+		r.Sessions = &Sessions{
+			Count:    t.sessions,
+			Bust:     t.busts,
+			StopLoss: t.stopLosses,
+			WinGoal:  t.winGoals,
+		}
+	}
+	for _, sb := range cfg.Options.SideBets {
+		st, ok := t.sideBets[sb.Name()]
+		if !ok {
+			continue
+		}
+		r.SideBets = append(r.SideBets, SideBet{
+			Name:    sb.Name(),
+			Placed:  st.placed,
+			Wagered: st.wagered,
+			Net:     st.net,
+			Edge:    float64(st.net) / float64(st.wagered),
+			HitRate: float64(st.wins) / float64(st.placed),
+		})
+	}
+	r.Freq = Frequencies{
+		Win:       float64(t.wins) / n,
+		Loss:      float64(t.losses) / n,
+		Push:      float64(t.pushes) / n,
+		Blackjack: float64(t.naturals) / n,
+		Surrender: float64(t.surrenders) / n,
+	}
+	return r
+}
+
+// WriteText writes the report as an aligned, human-readable table.
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Seed:\t%d\n", r.Seed)
+	fmt.Fprintf(tw, "Rounds:\t%d\n", r.Rounds)
+	fmt.Fprintf(tw, "Wagered:\t%d\n", r.Wagered)
+	fmt.Fprintf(tw, "Net:\t%d\n", r.Net)
+	fmt.Fprintf(tw, "EV per round:\t%.4f (95%% CI %.4f to %.4f)\n", r.EV, r.EVLow, r.EVHigh)
+	fmt.Fprintf(tw, "Edge:\t%.3f%%\n", 100*r.Edge)
+	fmt.Fprintf(tw, "Std. deviation:\t%.4f\n", r.StdDev)
+	if r.Ruin != nil {
+		fmt.Fprintf(tw, "Risk of ruin:\t%.3f%%\n", 100**r.Ruin)
+	}
+	fmt.Fprintf(tw, "Wins:\t%.3f%%\n", 100*r.Freq.Win)
+	fmt.Fprintf(tw, "Losses:\t%.3f%%\n", 100*r.Freq.Loss)
+	fmt.Fprintf(tw, "Pushes:\t%.3f%%\n", 100*r.Freq.Push)
+	fmt.Fprintf(tw, "Blackjacks:\t%.3f%%\n", 100*r.Freq.Blackjack)
+	fmt.Fprintf(tw, "Surrenders:\t%.3f%%\n", 100*r.Freq.Surrender)
+	if s := r.Sessions; s != nil {
+		fmt.Fprintf(tw, "Sessions:\t%d\n", s.Count)
+		fmt.Fprintf(tw, "Went bust:\t%d (%.3f%%)\n", s.Bust, 100*s.BustRate())
+		fmt.Fprintf(tw, "Hit stop-loss:\t%d\n", s.StopLoss)
+		fmt.Fprintf(tw, "Reached win goal:\t%d\n", s.WinGoal)
+	}
+	for _, sb := range r.SideBets {
+		fmt.Fprintf(tw, "Side bet %s:\t%d placed, %d wagered, net %d, edge %.3f%%, hit rate %.3f%%\n",
+			sb.Name, sb.Placed, sb.Wagered, sb.Net, 100*sb.Edge, 100*sb.HitRate)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as a JSON object.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// csvHeader names the columns written by WriteCSV.
+var csvHeader = []string{
+	"seed", "rounds", "wagered", "net", "ev", "ev_low", "ev_high", "edge", "std_dev",
+	"risk_of_ruin", "win", "loss", "push", "blackjack", "surrender",
+	"sessions", "bust", "stop_loss", "win_goal",
+}
+
+// WriteCSV writes the report as a CSV header row followed by a row of
+// values. The risk of ruin is left empty if it wasn't estimated, as are
+// the session counts if no sessions were played. Each side bet placed
+// adds columns prefixed with its name.
+func (r Report) WriteCSV(w io.Writer) error {
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	var ruin string
+	if r.Ruin != nil {
+		ruin = f(*r.Ruin)
+	}
+	sessions := make([]string, 4)
+	if s := r.Sessions; s != nil {
+		for i, n := range []int64{s.Count, s.Bust, s.StopLoss, s.WinGoal} {
+			sessions[i] = strconv.FormatInt(n, 10)
+		}
+	}
+	header := append([]string(nil), csvHeader...)
+	var sideBets []string
+	for _, sb := range r.SideBets {
+		for _, col := range []string{"placed", "wagered", "net", "edge", "hit_rate"} {
+			header = append(header, sb.Name+"_"+col)
+		}
+		sideBets = append(sideBets,
+			strconv.FormatInt(sb.Placed, 10),
+			strconv.FormatInt(sb.Wagered, 10),
+			strconv.FormatInt(sb.Net, 10),
+			f(sb.Edge), f(sb.HitRate))
+	}
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	row := append([]string{
+		strconv.FormatInt(r.Seed, 10),
+		strconv.FormatInt(r.Rounds, 10),
+		strconv.FormatInt(r.Wagered, 10),
+		strconv.FormatInt(r.Net, 10),
+		f(r.EV), f(r.EVLow), f(r.EVHigh), f(r.Edge), f(r.StdDev), ruin,
+		f(r.Freq.Win), f(r.Freq.Loss), f(r.Freq.Push), f(r.Freq.Blackjack), f(r.Freq.Surrender),
+	}, sessions...)
+	cw.Write(append(row, sideBets...))
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,270 @@
+// Package simulation plays large numbers of blackjack hands in parallel
+// to measure how an AI performs under a set of table rules.
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+)
+
+// Config describes a simulation.
+type Config struct {
+	// Options are the table rules for every game played. Options.NHands
+	// is ignored in favour of Hands, and Options.Seed seeds the seeds of
+	// each worker's game, so a simulation can be reproduced exactly with
+	// the same number of Workers.
+	Options blackjack.Options
+	Hands   int // the total number of rounds to play
+	Workers int // the number of games played in parallel; defaults to the number of CPUs
+	// NewAI returns the AI for each worker's game, and for each session
+	// when the worker plays sessions. Each call must return an AI that
+	// is safe to use independently of the others.
+	NewAI func() blackjack.AI
+	// Bankroll is the number of chips the player starts with, used to
+	// estimate the risk of ruin. 0 skips the estimate.
+	Bankroll int
+	// SessionHands is the most rounds played in each session when the
+	// Options or the AI set session rules, in which case each worker
+	// plays sessions in turn, starting each with a fresh bankroll, and
+	// the report counts how they ended. 0 plays each session until its
+	// rules end it.
+	SessionHands int
+}
+
+// Run plays the simulation described by cfg and reports the results.
+func Run(cfg Config) (Report, error) {
+	if cfg.NewAI == nil {
+		return Report{}, errors.New("simulation: Config.NewAI is required")
+	}
+	if cfg.Hands <= 0 {
+		return Report{}, errors.New("simulation: Config.Hands must be positive")
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if cfg.Workers > cfg.Hands {
+		cfg.Workers = cfg.Hands
+	}
+	if cfg.Options.Seed == nil {
+		cfg.Options.Seed = blackjack.Seed(time.Now().UnixNano())
+	}
+
+	seeds := rand.New(rand.NewSource(*cfg.Options.Seed))
+	results := make([]tally, cfg.Workers)
+	errs := make([]error, cfg.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Workers; i++ {
+		opts := cfg.Options
+		opts.NHands = cfg.Hands / cfg.Workers
+		if i < cfg.Hands%cfg.Workers {
+			opts.NHands++
+		}
+		opts.Seed = blackjack.Seed(seeds.Int63())
+
+		wg.Add(1)
+		go func(t *tally, err *error, opts blackjack.Options) {
+			defer wg.Done()
+			*err = play(cfg, opts, t)
+		}(&results[i], &errs[i], opts)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return Report{}, fmt.Errorf("simulation: %w", err)
+		}
+	}
+
+	var total tally
+	for _, t := range results {
+		total.merge(t)
+	}
+	return newReport(cfg, total), nil
+}
+
+// play plays a worker's share of the simulation with the options,
+// adding the results to t. If the player has session rules, it plays
+// sessions until it has played opts.NHands rounds.
+func play(cfg Config, opts blackjack.Options, t *tally) error {
+	r := &recorder{Wrapper: blackjack.Wrapper{AI: cfg.NewAI()}, tally: t}
+	session := blackjack.Session{
+		Bankroll: opts.Bankroll,
+		StopLoss: opts.StopLoss,
+		WinGoal:  opts.WinGoal,
+	}.With(r.Session())
+	if session == (blackjack.Session{}) {
+		g := blackjack.New(opts)
+		g.Observe(r)
+		_, err := g.Play(r)
+		return err
+	}
+
+	minBet := opts.WithDefaults().MinBet
+	seeds := rand.New(rand.NewSource(*opts.Seed))
+	hands := int64(opts.NHands)
+	for t.rounds < hands {
+		opts.NHands = int(hands - t.rounds)
+		if cfg.SessionHands > 0 && cfg.SessionHands < opts.NHands {
+			opts.NHands = cfg.SessionHands
+		}
+		opts.Seed = blackjack.Seed(seeds.Int63())
+		rounds := t.rounds
+		if t.sessions > 0 {
+			r.Wrapper = blackjack.Wrapper{AI: cfg.NewAI()}
+		}
+
+		g := blackjack.New(opts)
+		g.Observe(r)
+		balance, err := g.Play(r)
+		if err != nil {
+			return err
+		}
+		if t.rounds == rounds {
+			return fmt.Errorf("a session of %+v ended before a round was played", session)
+		}
+		t.sessions++
+		switch session.End(balance, minBet) {
+		case blackjack.Bust:
+			t.busts++
+		case blackjack.StoppedLoss:
+			t.stopLosses++
+		case blackjack.ReachedGoal:
+			t.winGoals++
+		}
+	}
+	return nil
+}
+
+// tally accumulates the results of the rounds played by one game.
+type tally struct {
+	rounds     int64
+	wagered    int64 // the sum of the opening bets
+	net        int64
+	sumSquares float64 // the sum of the squared net result of each round
+
+	wins, losses, pushes, naturals, surrenders int64
+
+	sessions, busts, stopLosses, winGoals int64
+
+	sideBets map[string]sideTally // by name
+}
+
+// sideTally accumulates the results of a side bet.
+type sideTally struct {
+	placed, wagered, net, wins int64
+}
+
+func (t *tally) add(bet int, r blackjack.Result) {
+	net := r.Net
+	for _, sb := range r.SideBets {
+		net -= sb.Winnings
+		t.addSideBet(sb)
+	}
+
+	t.rounds++
+	t.wagered += int64(bet)
+	t.net += int64(net)
+	t.sumSquares += float64(net) * float64(net)
+
+	for _, h := range r.Hands {
+		switch h.Outcome {
+		case blackjack.Natural:
+			t.naturals++
+			return
+		case blackjack.Surrendered:
+			t.surrenders++
+			return
+		}
+	}
+	switch {
+	case net > 0:
+		t.wins++
+	case net < 0:
+		t.losses++
+	default:
+		t.pushes++
+	}
+}
+
+func (t *tally) addSideBet(r blackjack.SideBetResult) {
+	if t.sideBets == nil {
+		t.sideBets = make(map[string]sideTally)
+	}
+	st := t.sideBets[r.Name]
+	st.placed++
+	st.wagered += int64(r.Bet)
+	st.net += int64(r.Winnings)
+	if r.Winnings > 0 {
+		st.wins++
+	}
+	t.sideBets[r.Name] = st
+}
+
+func (t *tally) merge(o tally) {
+	t.rounds += o.rounds
+	t.wagered += o.wagered
+	t.net += o.net
+	t.sumSquares += o.sumSquares
+	t.wins += o.wins
+	t.losses += o.losses
+	t.pushes += o.pushes
+	t.naturals += o.naturals
+	t.surrenders += o.surrenders
+	t.sessions += o.sessions
+	t.busts += o.busts
+	t.stopLosses += o.stopLosses
+	t.winGoals += o.winGoals
+	for name, ost := range o.sideBets {
+		if t.sideBets == nil {
+			t.sideBets = make(map[string]sideTally)
+		}
+		st := t.sideBets[name]
+		st.placed += ost.placed
+		st.wagered += ost.wagered
+		st.net += ost.net
+		st.wins += ost.wins
+		t.sideBets[name] = st
+	}
+}
+
+// recorder wraps an AI to tally the result of each round it plays.
+type recorder struct {
+	blackjack.Wrapper
+	tally *tally
+	bet   int // the bet placed on the round in play
+}
+
+// Observe notes the bet the game placed for the AI, which is the table
+// minimum if it asked for too many invalid bets.
+func (r *recorder) Observe(e blackjack.Event) {
+	if e.Kind == blackjack.HandStarted {
+		r.bet = e.Bet
+	}
+}
+
+func (r *recorder) Result(res blackjack.Result) {
+	r.tally.add(r.bet, res)
+	r.Wrapper.Result(res)
+}
+
+// z95 is the z-score of a two-sided 95% confidence interval.
+const z95 = 1.959964
+
+// riskOfRuin estimates the chance of losing a bankroll of the given
+// size before it grows without bound, for a game whose rounds have the
+// given mean and standard deviation.
+func riskOfRuin(bankroll int, mean, stdDev float64) float64 {
+	if mean <= 0 {
+		return 1
+	}
+	if stdDev == 0 {
+		return 0
+	}
+	return math.Exp(-2 * mean * float64(bankroll) / (stdDev * stdDev))
+}
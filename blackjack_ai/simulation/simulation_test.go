@@ -0,0 +1,233 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/sidebet"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// standAI always bets 100 and stands.
+type standAI struct{}
+
+func (ai standAI) Bet(shuffled bool) int {
+	return 100
+}
+
+func (ai standAI) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	return blackjack.MoveStand
+}
+
+func (ai standAI) Outcome(hand [][]deck.Card, dealer []deck.Card) {}
+
+func newStandAI() blackjack.AI {
+	return standAI{}
+}
+
+func TestRun(t *testing.T) {
+	cfg := Config{
+		Options:  blackjack.Options{Seed: blackjack.Seed(1)},
+		Hands:    20000,
+		Workers:  4,
+		NewAI:    newStandAI,
+		Bankroll: 10000,
+	}
+	r, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Rounds != int64(cfg.Hands) {
+		t.Errorf("played %d rounds, want %d", r.Rounds, cfg.Hands)
+	}
+	if r.EV >= 0 {
+		t.Errorf("always standing has EV %.2f, want a loss", r.EV)
+	}
+	if r.EVLow > r.EV || r.EVHigh < r.EV {
+		t.Errorf("EV %.2f outside its confidence interval [%.2f, %.2f]", r.EV, r.EVLow, r.EVHigh)
+	}
+	freqs := r.Freq.Win + r.Freq.Loss + r.Freq.Push + r.Freq.Blackjack + r.Freq.Surrender
+	if math.Abs(freqs-1) > 1e-9 {
+		t.Errorf("frequencies sum to %f, want 1", freqs)
+	}
+	if r.Ruin == nil || *r.Ruin != 1 {
+		t.Errorf("risk of ruin is %v, want 1 for a losing game", r.Ruin)
+	}
+
+	again, _ := Run(cfg)
+	if again.Net != r.Net {
+		t.Errorf("rerunning with the same seed won %d, want %d", again.Net, r.Net)
+	}
+}
+
+// overBetAI always asks to bet more than the table maximum.
+type overBetAI struct{ standAI }
+
+func (ai overBetAI) Bet(shuffled bool) int {
+	return 1000
+}
+
+func TestRunWageredAfterInvalidBets(t *testing.T) {
+	// The game bets the minimum for an AI whose bets are all too big, and
+	// only that is wagered.
+	r, err := Run(Config{
+		Options: blackjack.Options{Seed: blackjack.Seed(1), MaxBet: 500},
+		Hands:   100,
+		NewAI:   func() blackjack.AI { return overBetAI{} },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := r.Rounds * 100; r.Wagered != want {
+		t.Errorf("wagered %d, want the table minimum on each of %d rounds", r.Wagered, r.Rounds)
+	}
+}
+
+func TestRunSessions(t *testing.T) {
+	// Standing on everything loses about 15% a round, so nearly every
+	// session goes bust before it can win 300.
+	cfg := Config{
+		Options:      blackjack.Options{Seed: blackjack.Seed(1), Bankroll: 1000, WinGoal: 300},
+		Hands:        10000,
+		Workers:      2,
+		NewAI:        newStandAI,
+		SessionHands: 500,
+	}
+	r, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Rounds != int64(cfg.Hands) {
+		t.Errorf("played %d rounds, want %d", r.Rounds, cfg.Hands)
+	}
+	s := r.Sessions
+	if s == nil {
+		t.Fatal("no sessions reported")
+	}
+	if s.Count < int64(cfg.Hands/cfg.SessionHands) {
+		t.Errorf("played %d sessions, want at least %d", s.Count, cfg.Hands/cfg.SessionHands)
+	}
+	if s.Bust+s.WinGoal > s.Count || s.StopLoss != 0 {
+		t.Errorf("sessions ended %+v", *s)
+	}
+	if rate := s.BustRate(); rate < 0.5 {
+		t.Errorf("bust rate is %.2f, want most sessions to go bust", rate)
+	}
+
+	if r, _ := Run(Config{Options: blackjack.Options{Seed: blackjack.Seed(1)}, Hands: 100, NewAI: newStandAI}); r.Sessions != nil {
+		t.Errorf("reported sessions %+v without session rules", *r.Sessions)
+	}
+	cfg.Options.Bankroll = 50
+	if _, err := Run(cfg); err == nil {
+		t.Error("expected an error with a bankroll below the minimum bet")
+	}
+}
+
+// sideBetAI is a standAI that wagers 10 on every side bet.
+type sideBetAI struct {
+	standAI
+}
+
+func (ai sideBetAI) SideBets(offered []blackjack.SideBet) []int {
+	bets := make([]int, len(offered))
+	for i := range bets {
+		bets[i] = 10
+	}
+	return bets
+}
+
+func TestRunSideBets(t *testing.T) {
+	cfg := Config{
+		Options: blackjack.Options{Seed: blackjack.Seed(1), SideBets: sidebet.All},
+		Hands:   20000,
+		Workers: 2,
+		NewAI:   func() blackjack.AI { return sideBetAI{} },
+	}
+	r, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Side bets are left out of the figures for the main bet, which are
+	// those of a player who places none on the same cards.
+	cfg.NewAI = newStandAI
+	without, _ := Run(cfg)
+	if r.Net != without.Net || r.Wagered != without.Wagered {
+		t.Errorf("side bets changed the net to %d on %d wagered, want %d on %d",
+			r.Net, r.Wagered, without.Net, without.Wagered)
+	}
+
+	if len(r.SideBets) != len(sidebet.All) {
+		t.Fatalf("reported %d side bets, want %d", len(r.SideBets), len(sidebet.All))
+	}
+	for i, sb := range r.SideBets {
+		if sb.Name != sidebet.All[i].Name() {
+			t.Errorf("side bet %d is %s, want %s", i, sb.Name, sidebet.All[i].Name())
+		}
+		if sb.Placed != r.Rounds || sb.Wagered != 10*r.Rounds {
+			t.Errorf("%s placed %d times for %d, want every round", sb.Name, sb.Placed, sb.Wagered)
+		}
+		if sb.HitRate <= 0 || sb.HitRate >= 0.25 {
+			t.Errorf("%s hit %.3f of the time", sb.Name, sb.HitRate)
+		}
+	}
+
+	var c bytes.Buffer
+	r.WriteCSV(&c)
+	if !strings.Contains(c.String(), "lucky-ladies_edge") {
+		t.Errorf("CSV report missing side bet columns:\n%s", c.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if _, err := Run(Config{Hands: 10}); err == nil {
+		t.Error("expected an error without NewAI")
+	}
+	if _, err := Run(Config{NewAI: newStandAI}); err == nil {
+		t.Error("expected an error without Hands")
+	}
+}
+
+func TestReportOutput(t *testing.T) {
+	r, err := Run(Config{Options: blackjack.Options{Seed: blackjack.Seed(1)}, Hands: 100, NewAI: newStandAI})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var text bytes.Buffer
+	if err := r.WriteText(&text); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	if !strings.Contains(text.String(), "Rounds:") {
+		t.Errorf("text report missing rounds:\n%s", text.String())
+	}
+
+	var js bytes.Buffer
+	if err := r.WriteJSON(&js); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatalf("decoding JSON report: %v", err)
+	}
+	if decoded.Rounds != r.Rounds || decoded.Net != r.Net {
+		t.Errorf("JSON report decoded to %+v, want %+v", decoded, r)
+	}
+
+	var c bytes.Buffer
+	if err := r.WriteCSV(&c); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	rows, err := csv.NewReader(&c).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV report: %v", err)
+	}
+	if len(rows) != 2 || len(rows[0]) != len(rows[1]) {
+		t.Errorf("CSV report has rows %v, want a header and a row of values", rows)
+	}
+}
@@ -0,0 +1,181 @@
+package strategy
+
+import (
+	"errors"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// StrategyAI is a blackjack AI that bets the table minimum and plays
+// every hand by a strategy chart. It never takes insurance.
+type StrategyAI struct {
+	chart    *Chart
+	table    table
+	round    round
+	prev     round          // the round before the last move
+	sideBets map[string]int // the wager on each side bet, by name
+}
+
+// NewAI returns an AI playing by the chart at a table with the given
+// rules, which it needs to know which of the chart's actions are
+// allowed.
+func NewAI(chart *Chart, opts blackjack.Options) *StrategyAI {
+	return &StrategyAI{chart: chart, table: newTable(opts)}
+}
+
+func (ai *StrategyAI) Bet(shuffled bool) int {
+	ai.round = round{hands: 1}
+	return ai.table.minBet
+}
+
+// WithSideBets sets the AI to wager the given amount on each side bet,
+// by name, at tables that offer it, and returns the AI.
+func (ai *StrategyAI) WithSideBets(wagers map[string]int) *StrategyAI {
+	ai.sideBets = wagers
+	return ai
+}
+
+func (ai *StrategyAI) SideBets(offered []blackjack.SideBet) []int {
+	return sideBets(ai.sideBets, offered)
+}
+
+func (ai *StrategyAI) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	move, _ := ai.table.decide(ai.chart, ai.round, hand, dealer)
+	ai.prev = ai.round
+	ai.round.played(move, hand)
+	return move
+}
+
+// Rejected takes back the last move if it was rejected, which happens
+// only to a double or split the player hasn't the chips for, and plays
+// the rest of the round without doubling or splitting.
+func (ai *StrategyAI) Rejected(err error) {
+	if errors.Is(err, blackjack.ErrInvalidMove) {
+		ai.round = ai.prev.short()
+	}
+}
+
+func (ai *StrategyAI) Outcome(hand [][]deck.Card, dealer []deck.Card) {
+	// noop
+}
+
+// sideBets returns the wagers on the offered side bets.
+func sideBets(wagers map[string]int, offered []blackjack.SideBet) []int {
+	if len(wagers) == 0 {
+		return nil
+	}
+	bets := make([]int, len(offered))
+	for i, sb := range offered {
+		bets[i] = wagers[sb.Name()]
+	}
+	return bets
+}
+
+// table holds the rules that decide which of a chart's actions a
+// player may take.
+type table struct {
+	minBet           int
+	maxSplitHands    int
+	hitSplitAces     bool
+	resplitAces      bool
+	doubleAfterSplit bool
+	doubleOn         blackjack.DoubleRule
+	surrender        bool
+}
+
+// newTable reads the rules from the options.
+func newTable(opts blackjack.Options) table {
+	opts = opts.WithDefaults()
+	return table{
+		minBet:           opts.MinBet,
+		maxSplitHands:    opts.MaxSplitHands,
+		hitSplitAces:     opts.HitSplitAces,
+		resplitAces:      opts.ResplitAces,
+		doubleAfterSplit: !opts.NoDoubleAfterSplit,
+		doubleOn:         opts.DoubleOn,
+		surrender:        opts.LateSurrender || opts.EarlySurrender,
+	}
+}
+
+// round tracks the splits a player has made in the current round, and
+// whether they have run short of chips, which limit the moves open to
+// them.
+type round struct {
+	hands     int // the number of hands the player holds
+	splitAces bool
+	noChips   bool // the player hasn't the chips to double or split
+}
+
+// short returns the round for a player who hasn't the chips to double
+// or split. An AI that plays by the table's rules only has a move
+// rejected for want of chips.
+func (r round) short() round {
+	r.noChips = true
+	return r
+}
+
+// played updates the round after the player makes a move on the hand.
+func (r *round) played(move blackjack.Move, hand []deck.Card) {
+	if !sameMove(move, blackjack.MoveSplit) {
+		return
+	}
+	r.hands++
+	r.splitAces = hand[0].Rank == deck.Ace
+}
+
+// decide returns the move the chart calls for on the hand, given the
+// rules of the table and the player's splits so far, along with the
+// label of the chart row it came from.
+func (t table) decide(c *Chart, r round, hand []deck.Card, upcard deck.Card) (blackjack.Move, string) {
+	action, label := c.action(hand, upcard, t.canSplit(r, hand))
+	return t.move(action, r, hand), label
+}
+
+// canSplit reports whether the player may split the hand.
+func (t table) canSplit(r round, hand []deck.Card) bool {
+	return isPair(hand) && r.hands < t.maxSplitHands && (!r.splitAces || t.resplitAces) && !r.noChips
+}
+
+// move returns the move that carries out the action on the hand, or its
+// fallback if the rules forbid it.
+func (t table) move(action Action, r round, hand []deck.Card) blackjack.Move {
+	split := r.hands > 1
+	canDraw := !r.splitAces || t.hitSplitAces
+	canDouble := canDraw && len(hand) == 2 && (!split || t.doubleAfterSplit) && t.doubleOn.Allows(hand) && !r.noChips
+	canSurrender := t.surrender && !split && len(hand) == 2
+
+	var move blackjack.Move
+	switch action {
+	case Hit:
+		move = blackjack.MoveHit
+	case Stand:
+		move = blackjack.MoveStand
+	case DoubleOrHit:
+		move = choose(canDouble, blackjack.MoveDouble, blackjack.MoveHit)
+	case DoubleOrStand:
+		move = choose(canDouble, blackjack.MoveDouble, blackjack.MoveStand)
+	case Split:
+		move = blackjack.MoveSplit
+	case SplitIfDAS:
+		move = choose(t.doubleAfterSplit && !r.noChips, blackjack.MoveSplit, blackjack.MoveHit)
+	case SurrenderOrHit:
+		move = choose(canSurrender, blackjack.MoveSurrender, blackjack.MoveHit)
+	case SurrenderOrStand:
+		move = choose(canSurrender, blackjack.MoveSurrender, blackjack.MoveStand)
+	case SurrenderOrSplit:
+		move = choose(canSurrender, blackjack.MoveSurrender, blackjack.MoveSplit)
+	}
+	if !canDraw && !sameMove(move, blackjack.MoveSplit) {
+		// Split aces that can't be hit may only be resplit or stood on.
+		move = blackjack.MoveStand
+	}
+	return move
+}
+
+func choose(cond bool, yes, no blackjack.Move) blackjack.Move {
+	if cond {
+		return yes
+	}
+	return no
+}
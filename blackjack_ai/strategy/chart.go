@@ -0,0 +1,198 @@
+// Package strategy plays blackjack by the book: it looks up each
+// decision in a basic strategy chart, loaded from CSV or YAML, and
+// checks how closely other AIs follow one.
+package strategy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// Action is a decision in a strategy chart. Actions that depend on the
+// table rules name the move to fall back on when the rules forbid them.
+type Action uint8
+
+const (
+	Hit              Action = iota + 1
+	Stand                   // S
+	DoubleOrHit             // Dh: double if allowed, otherwise hit
+	DoubleOrStand           // Ds: double if allowed, otherwise stand
+	Split                   // P
+	SplitIfDAS              // Ph: split if doubling after splitting is allowed, otherwise hit
+	SurrenderOrHit          // Rh: surrender if allowed, otherwise hit
+	SurrenderOrStand        // Rs: surrender if allowed, otherwise stand
+	SurrenderOrSplit        // Rp: surrender if allowed, otherwise split
+	maxAction        = SurrenderOrSplit
+)
+
+// actionCodes are the abbreviations of each Action used in charts.
+var actionCodes = [...]string{
+	Hit:              "H",
+	Stand:            "S",
+	DoubleOrHit:      "Dh",
+	DoubleOrStand:    "Ds",
+	Split:            "P",
+	SplitIfDAS:       "Ph",
+	SurrenderOrHit:   "Rh",
+	SurrenderOrStand: "Rs",
+	SurrenderOrSplit: "Rp",
+}
+
+// String returns the action's chart abbreviation, e.g. "Dh".
+func (a Action) String() string {
+	if a == 0 || a > maxAction {
+		return fmt.Sprintf("Action(%d)", a)
+	}
+	return actionCodes[a]
+}
+
+// parseAction parses an action from its chart abbreviation. "D" is
+// accepted for Dh and "R" for Rh, as many charts write them.
+func parseAction(s string) (Action, error) {
+	switch strings.ToUpper(s) {
+	case "D":
+		return DoubleOrHit, nil
+	case "R":
+		return SurrenderOrHit, nil
+	}
+	for a := Hit; a <= maxAction; a++ {
+		if strings.EqualFold(s, actionCodes[a]) {
+			return a, nil
+		}
+	}
+	return 0, fmt.Errorf("strategy: invalid action %q", s)
+}
+
+// Upcards is the number of distinct dealer upcards: 2 to 10 and ace.
+const Upcards = 10
+
+// Row holds a chart's actions against each dealer upcard, from 2 at
+// index 0 to ace at index 9.
+type Row [Upcards]Action
+
+// upcardIndex returns the index in a Row of the dealer's upcard.
+func upcardIndex(upcard deck.Card) int {
+	if upcard.Rank == deck.Ace {
+		return Upcards - 1
+	}
+	return blackjack.Score(upcard) - 2
+}
+
+// upcardLabels are the column headings of a Row.
+var upcardLabels = [Upcards]string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "A"}
+
+// A Chart is a basic strategy chart. Hard and Soft are keyed by the
+// hand's total, and Pairs by the value of each card of the pair, with
+// aces counted as 11.
+//
+// Charts needn't list every hand. Totals above the highest row of Hard
+// or Soft are stood on and other missing totals hit, and pairs missing
+// from Pairs are played by their total.
+type Chart struct {
+	Name  string
+	Hard  map[int]Row
+	Soft  map[int]Row
+	Pairs map[int]Row
+}
+
+// NewChart returns an empty chart.
+func NewChart(name string) *Chart {
+	return &Chart{
+		Name:  name,
+		Hard:  make(map[int]Row),
+		Soft:  make(map[int]Row),
+		Pairs: make(map[int]Row),
+	}
+}
+
+// Action returns the chart's action for the hand against the dealer's
+// upcard. If split is false, a pair is played by its total, as when the
+// rules forbid splitting it.
+func (c *Chart) Action(hand []deck.Card, upcard deck.Card, split bool) Action {
+	a, _ := c.action(hand, upcard, split)
+	return a
+}
+
+// action returns the chart's action for the hand and the label of the
+// hand's row, e.g. "H16", or "P10" for a pair of tens the chart plays
+// as hard 20.
+func (c *Chart) action(hand []deck.Card, upcard deck.Card, split bool) (Action, string) {
+	col := upcardIndex(upcard)
+	total := blackjack.Score(hand...)
+	if split && isPair(hand) {
+		value := blackjack.Score(hand[0])
+		label := rowLabel("P", value)
+		if row, ok := c.Pairs[value]; ok {
+			return row[col], label
+		}
+		return c.total(total, blackjack.Soft(hand...), col), label
+	}
+	if blackjack.Soft(hand...) {
+		return c.total(total, true, col), rowLabel("S", total)
+	}
+	return c.total(total, false, col), rowLabel("H", total)
+}
+
+// total returns the chart's action for a hard or soft total.
+func (c *Chart) total(total int, soft bool, col int) Action {
+	if soft {
+		return lookup(c.Soft, total, col)
+	}
+	return lookup(c.Hard, total, col)
+}
+
+// lookup finds the action for the total in the rows, standing on totals
+// above the chart and hitting any others it doesn't list.
+func lookup(rows map[int]Row, total, col int) Action {
+	if row, ok := rows[total]; ok {
+		return row[col]
+	}
+	highest := 0
+	for t := range rows {
+		if t > highest {
+			highest = t
+		}
+	}
+	if total > highest {
+		return Stand
+	}
+	return Hit
+}
+
+// isPair reports whether the hand is a pair that may be split.
+func isPair(hand []deck.Card) bool {
+	return len(hand) == 2 && hand[0].Rank == hand[1].Rank
+}
+
+// validate checks that the chart's rows describe possible hands.
+func (c *Chart) validate() error {
+	for total := range c.Hard {
+		if total < 4 || total > 21 {
+			return fmt.Errorf("strategy: invalid hard total %d", total)
+		}
+	}
+	for total := range c.Soft {
+		if total < 12 || total > 21 {
+			return fmt.Errorf("strategy: invalid soft total %d", total)
+		}
+	}
+	for value := range c.Pairs {
+		if value < 2 || value > 11 {
+			return fmt.Errorf("strategy: invalid pair value %d", value)
+		}
+	}
+	rows := []map[int]Row{c.Hard, c.Soft, c.Pairs}
+	for _, rs := range rows {
+		for _, row := range rs {
+			for _, a := range row {
+				if a == 0 || a > maxAction {
+					return fmt.Errorf("strategy: chart %q has missing or invalid actions", c.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package strategy
+
+import (
+	"strings"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+)
+
+// The built-in charts are basic strategy for four to eight decks,
+// assuming doubling after splitting and late surrender are allowed.
+// Under other rules, StrategyAI falls back on the alternative each
+// action names.
+const (
+	s17CSV = `hand,2,3,4,5,6,7,8,9,10,A
+H8,H,H,H,H,H,H,H,H,H,H
+H9,H,Dh,Dh,Dh,Dh,H,H,H,H,H
+H10,Dh,Dh,Dh,Dh,Dh,Dh,Dh,Dh,H,H
+H11,Dh,Dh,Dh,Dh,Dh,Dh,Dh,Dh,Dh,H
+H12,H,H,S,S,S,H,H,H,H,H
+H13,S,S,S,S,S,H,H,H,H,H
+H14,S,S,S,S,S,H,H,H,H,H
+H15,S,S,S,S,S,H,H,H,Rh,H
+H16,S,S,S,S,S,H,H,Rh,Rh,Rh
+H17,S,S,S,S,S,S,S,S,S,S
+S13,H,H,H,Dh,Dh,H,H,H,H,H
+S14,H,H,H,Dh,Dh,H,H,H,H,H
+S15,H,H,Dh,Dh,Dh,H,H,H,H,H
+S16,H,H,Dh,Dh,Dh,H,H,H,H,H
+S17,H,Dh,Dh,Dh,Dh,H,H,H,H,H
+S18,S,Ds,Ds,Ds,Ds,S,S,H,H,H
+S19,S,S,S,S,S,S,S,S,S,S
+P2,Ph,Ph,P,P,P,P,H,H,H,H
+P3,Ph,Ph,P,P,P,P,H,H,H,H
+P4,H,H,H,Ph,Ph,H,H,H,H,H
+P6,Ph,P,P,P,P,H,H,H,H,H
+P7,P,P,P,P,P,P,H,H,H,H
+P8,P,P,P,P,P,P,P,P,P,P
+P9,P,P,P,P,P,S,P,P,S,S
+PA,P,P,P,P,P,P,P,P,P,P
+`
+
+	h17CSV = `hand,2,3,4,5,6,7,8,9,10,A
+H8,H,H,H,H,H,H,H,H,H,H
+H9,H,Dh,Dh,Dh,Dh,H,H,H,H,H
+H10,Dh,Dh,Dh,Dh,Dh,Dh,Dh,Dh,H,H
+H11,Dh,Dh,Dh,Dh,Dh,Dh,Dh,Dh,Dh,Dh
+H12,H,H,S,S,S,H,H,H,H,H
+H13,S,S,S,S,S,H,H,H,H,H
+H14,S,S,S,S,S,H,H,H,H,H
+H15,S,S,S,S,S,H,H,H,Rh,Rh
+H16,S,S,S,S,S,H,H,Rh,Rh,Rh
+H17,S,S,S,S,S,S,S,S,S,Rs
+S13,H,H,H,Dh,Dh,H,H,H,H,H
+S14,H,H,H,Dh,Dh,H,H,H,H,H
+S15,H,H,Dh,Dh,Dh,H,H,H,H,H
+S16,H,H,Dh,Dh,Dh,H,H,H,H,H
+S17,H,Dh,Dh,Dh,Dh,H,H,H,H,H
+S18,Ds,Ds,Ds,Ds,Ds,S,S,H,H,H
+S19,S,S,S,S,Ds,S,S,S,S,S
+S20,S,S,S,S,S,S,S,S,S,S
+P2,Ph,Ph,P,P,P,P,H,H,H,H
+P3,Ph,Ph,P,P,P,P,H,H,H,H
+P4,H,H,H,Ph,Ph,H,H,H,H,H
+P6,Ph,P,P,P,P,H,H,H,H,H
+P7,P,P,P,P,P,P,H,H,H,H
+P8,P,P,P,P,P,P,P,P,P,Rp
+P9,P,P,P,P,P,S,P,P,S,S
+PA,P,P,P,P,P,P,P,P,P,P
+`
+)
+
+// S17 returns basic strategy for a dealer who stands on soft 17.
+func S17() *Chart {
+	return mustParse("4-8 decks, S17, DAS, late surrender", s17CSV)
+}
+
+// H17 returns basic strategy for a dealer who hits soft 17.
+func H17() *Chart {
+	return mustParse("4-8 decks, H17, DAS, late surrender", h17CSV)
+}
+
+// ChartFor returns the built-in chart for the table rules.
+func ChartFor(opts blackjack.Options) *Chart {
+	if opts.DealerStandsSoft17 {
+		return S17()
+	}
+	return H17()
+}
+
+func mustParse(name, chart string) *Chart {
+	c, err := ParseCSV(strings.NewReader(chart))
+	if err != nil {
+		panic(err)
+	}
+	c.Name = name
+	return c
+}
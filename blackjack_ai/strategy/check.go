@@ -0,0 +1,164 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// A Checker wraps an AI, passing on its decisions unchanged while
+// counting how often they deviate from a strategy chart.
+type Checker struct {
+	blackjack.Wrapper
+	chart *Chart
+	table table
+	round round
+	prev  round // the round before the last move
+
+	decisions  int
+	deviations map[deviation]int
+}
+
+// deviation is a decision on which an AI played differently to the
+// chart.
+type deviation struct {
+	hand, upcard string
+	want, got    string
+}
+
+// NewChecker returns a Checker comparing the AI's play to the chart at
+// a table with the given rules.
+func NewChecker(ai blackjack.AI, chart *Chart, opts blackjack.Options) *Checker {
+	return &Checker{
+		Wrapper:    blackjack.Wrapper{AI: ai},
+		chart:      chart,
+		table:      newTable(opts),
+		deviations: make(map[deviation]int),
+	}
+}
+
+func (c *Checker) Bet(shuffled bool) int {
+	c.round = round{hands: 1}
+	return c.AI.Bet(shuffled)
+}
+
+func (c *Checker) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	want, label := c.table.decide(c.chart, c.round, hand, dealer)
+	got := c.AI.Play(hand, dealer)
+	c.decisions++
+	if !sameMove(got, want) {
+		c.deviations[deviation{
+			hand:   label,
+			upcard: upcardLabels[upcardIndex(dealer)],
+			want:   moveName(want),
+			got:    moveName(got),
+		}]++
+	}
+	c.prev = c.round
+	c.round.played(got, hand)
+	return got
+}
+
+// Rejected takes back the last move if it was rejected, which happens
+// to a move within the rules only for want of chips, and checks the
+// rest of the round without doubling or splitting.
+func (c *Checker) Rejected(err error) {
+	if errors.Is(err, blackjack.ErrInvalidMove) {
+		c.round = c.prev.short()
+	}
+	c.Wrapper.Rejected(err)
+}
+
+// Report summarises the decisions checked so far.
+func (c *Checker) Report() Report {
+	r := Report{Chart: c.chart.Name, Decisions: c.decisions}
+	for d, n := range c.deviations {
+		r.Deviations = append(r.Deviations, Deviation{
+			Hand:   d.hand,
+			Upcard: d.upcard,
+			Want:   d.want,
+			Got:    d.got,
+			Count:  n,
+		})
+	}
+	sort.Slice(r.Deviations, func(i, j int) bool {
+		a, b := r.Deviations[i], r.Deviations[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Hand != b.Hand {
+			return a.Hand < b.Hand
+		}
+		return a.Upcard < b.Upcard
+	})
+	return r
+}
+
+// Report is the result of checking an AI against a chart.
+type Report struct {
+	Chart      string
+	Decisions  int
+	Deviations []Deviation // the most frequent first
+}
+
+// Deviation counts the times an AI made a different move to the chart
+// with a hand against an upcard.
+type Deviation struct {
+	Hand   string // the chart row, e.g. "H16", "S18" or "PA"
+	Upcard string // the chart column, e.g. "10" or "A"
+	Want   string // the chart's move
+	Got    string // the AI's move
+	Count  int
+}
+
+// Total returns the number of decisions that deviated from the chart.
+func (r Report) Total() int {
+	total := 0
+	for _, d := range r.Deviations {
+		total += d.Count
+	}
+	return total
+}
+
+// Rate returns the fraction of decisions that deviated from the chart.
+func (r Report) Rate() float64 {
+	if r.Decisions == 0 {
+		return 0
+	}
+	return float64(r.Total()) / float64(r.Decisions)
+}
+
+// WriteText writes the report as a table of deviations.
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "chart:\t%s\n", r.Chart)
+	fmt.Fprintf(tw, "decisions:\t%d\n", r.Decisions)
+	fmt.Fprintf(tw, "deviations:\t%d (%.2f%%)\n", r.Total(), 100*r.Rate())
+	if len(r.Deviations) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "hand\tupcard\tchart\tplayed\tcount")
+		for _, d := range r.Deviations {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\n", d.Hand, d.Upcard, d.Want, d.Got, d.Count)
+		}
+	}
+	return tw.Flush()
+}
+
+// sameMove reports whether a and b are the same move.
+func sameMove(a, b blackjack.Move) bool {
+	return blackjack.MoveName(a) == blackjack.MoveName(b)
+}
+
+// moveName names the move for a report, calling any the table doesn't
+// know "unknown".
+func moveName(m blackjack.Move) string {
+	if name := blackjack.MoveName(m); name != "" {
+		return name
+	}
+	return "unknown"
+}
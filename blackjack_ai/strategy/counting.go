@@ -0,0 +1,195 @@
+package strategy
+
+import (
+	"errors"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/bankroll"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/counting"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// An Index is a play that departs from the chart when the count reaches
+// a given value.
+type Index struct {
+	Hand   string  // the chart row, e.g. "H16" or "P10"
+	Upcard string  // the chart column, e.g. "10" or "A"
+	Count  float64 // the index
+	Action Action  // the action to play at or above the index
+	// Below plays Action below the index instead, for deviations made
+	// when the shoe is rich in low cards.
+	Below bool
+}
+
+// applies reports whether the index overrides the chart at the count.
+func (i Index) applies(count float64) bool {
+	if i.Below {
+		return count < i.Count
+	}
+	return count >= i.Count
+}
+
+// Illustrious18 are the Hi-Lo indexes for the most valuable deviations
+// from multi-deck basic strategy. The eighteenth, taking insurance at
+// +3, is IllustriousInsurance.
+var Illustrious18 = []Index{
+	{Hand: "H16", Upcard: "10", Count: 0, Action: Stand},
+	{Hand: "H15", Upcard: "10", Count: 4, Action: Stand},
+	{Hand: "P10", Upcard: "5", Count: 5, Action: Split},
+	{Hand: "P10", Upcard: "6", Count: 4, Action: Split},
+	{Hand: "H10", Upcard: "10", Count: 4, Action: DoubleOrHit},
+	{Hand: "H12", Upcard: "3", Count: 2, Action: Stand},
+	{Hand: "H12", Upcard: "2", Count: 3, Action: Stand},
+	{Hand: "H11", Upcard: "A", Count: 1, Action: DoubleOrHit},
+	{Hand: "H9", Upcard: "2", Count: 1, Action: DoubleOrHit},
+	{Hand: "H10", Upcard: "A", Count: 4, Action: DoubleOrHit},
+	{Hand: "H9", Upcard: "7", Count: 3, Action: DoubleOrHit},
+	{Hand: "H16", Upcard: "9", Count: 5, Action: Stand},
+	{Hand: "H13", Upcard: "2", Count: -1, Action: Hit, Below: true},
+	{Hand: "H12", Upcard: "4", Count: 0, Action: Hit, Below: true},
+	{Hand: "H12", Upcard: "5", Count: -2, Action: Hit, Below: true},
+	{Hand: "H12", Upcard: "6", Count: -1, Action: Hit, Below: true},
+	{Hand: "H13", Upcard: "3", Count: -2, Action: Hit, Below: true},
+}
+
+// IllustriousInsurance is the Hi-Lo true count at which insurance
+// becomes a good bet.
+const IllustriousInsurance = 3
+
+// CountingConfig configures a CountingAI.
+type CountingConfig struct {
+	System counting.System
+	Ramp   counting.Ramp // defaults to the System's Ramp
+	Unit   int           // the chips in a betting unit; defaults to the table minimum
+	// Indexes are the plays that depart from the chart by the count,
+	// which are checked in order.
+	Indexes []Index
+	// InsureAt is the count at or above which to take insurance. 0
+	// never takes it.
+	InsureAt float64
+	// Kelly is the fraction of the Kelly bet to make at tables with a
+	// bankroll, e.g. 0.5 for half Kelly, estimating the edge from the
+	// true count as if it were Hi-Lo's. 0 bets by the Ramp.
+	Kelly float64
+}
+
+// CountingAI plays by a strategy chart, counting every card it sees to
+// size its bets and to make index plays.
+type CountingAI struct {
+	chart    *Chart
+	table    table
+	round    round
+	prev     round // the round before the last move
+	config   CountingConfig
+	maxBet   int
+	counter  *counting.Counter
+	sideBets map[string]int  // the wager on each side bet, by name
+	sizer    *bankroll.Sizer // sizes bets by the Kelly criterion, if set
+	chips    int             // the chips the player has, if the sizer is set
+}
+
+// NewCountingAI returns an AI that counts cards at a table with the
+// given rules, playing by the chart when no index applies.
+func NewCountingAI(chart *Chart, opts blackjack.Options, config CountingConfig) *CountingAI {
+	opts = opts.WithDefaults()
+	if config.Ramp == nil {
+		config.Ramp = config.System.Ramp
+	}
+	if config.Unit == 0 {
+		config.Unit = opts.MinBet
+	}
+	ai := &CountingAI{
+		chart:   chart,
+		table:   newTable(opts),
+		config:  config,
+		maxBet:  opts.MaxBet,
+		counter: counting.NewCounter(config.System, opts.NDecks),
+	}
+	if config.Kelly > 0 && opts.Bankroll > 0 {
+		ai.sizer = &bankroll.Sizer{
+			Fraction: config.Kelly,
+			Unit:     config.Unit,
+			MinBet:   opts.MinBet,
+			MaxBet:   opts.MaxBet,
+		}
+		ai.chips = opts.Bankroll
+	}
+	return ai
+}
+
+// Count returns the AI's true count.
+func (ai *CountingAI) Count() float64 {
+	return ai.counter.True()
+}
+
+func (ai *CountingAI) Bet(shuffled bool) int {
+	if shuffled {
+		ai.counter.Reset()
+	}
+	ai.round = round{hands: 1}
+
+	if ai.sizer != nil {
+		return ai.sizer.Bet(ai.chips, bankroll.CountEdge(bankroll.BaseEdge, ai.Count()))
+	}
+	bet := ai.config.Unit * ai.config.Ramp.Units(ai.Count())
+	if bet < ai.table.minBet {
+		bet = ai.table.minBet
+	}
+	if ai.maxBet > 0 && bet > ai.maxBet {
+		bet = ai.maxBet
+	}
+	return bet
+}
+
+// WithSideBets sets the AI to wager the given amount on each side bet,
+// by name, at tables that offer it, and returns the AI.
+func (ai *CountingAI) WithSideBets(wagers map[string]int) *CountingAI {
+	ai.sideBets = wagers
+	return ai
+}
+
+func (ai *CountingAI) SideBets(offered []blackjack.SideBet) []int {
+	return sideBets(ai.sideBets, offered)
+}
+
+func (ai *CountingAI) Play(hand []deck.Card, dealer deck.Card) blackjack.Move {
+	action, label := ai.chart.action(hand, dealer, ai.table.canSplit(ai.round, hand))
+	upcard := upcardLabels[upcardIndex(dealer)]
+	count := ai.Count()
+	for _, i := range ai.config.Indexes {
+		if i.Hand == label && i.Upcard == upcard && i.applies(count) {
+			action = i.Action
+			break
+		}
+	}
+	move := ai.table.move(action, ai.round, hand)
+	ai.prev = ai.round
+	ai.round.played(move, hand)
+	return move
+}
+
+// Rejected takes back the last move if it was rejected, which happens
+// only to a double or split the player hasn't the chips for, and plays
+// the rest of the round without doubling or splitting.
+func (ai *CountingAI) Rejected(err error) {
+	if errors.Is(err, blackjack.ErrInvalidMove) {
+		ai.round = ai.prev.short()
+	}
+}
+
+func (ai *CountingAI) Insurance(hand []deck.Card, dealer deck.Card) bool {
+	return ai.config.InsureAt != 0 && ai.Count() >= ai.config.InsureAt
+}
+
+func (ai *CountingAI) Seen(card deck.Card) {
+	ai.counter.Seen(card)
+}
+
+// Result keeps track of the player's chips for sizing Kelly bets.
+func (ai *CountingAI) Result(r blackjack.Result) {
+	ai.chips += r.Net
+}
+
+func (ai *CountingAI) Outcome(hand [][]deck.Card, dealer []deck.Card) {
+	// noop
+}
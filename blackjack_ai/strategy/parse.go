@@ -0,0 +1,291 @@
+package strategy
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Charts are written as tables with a row for each hand and a column
+// for each dealer upcard, using the abbreviations H, S, Dh, Ds, P, Ph,
+// Rh, Rs and Rp for actions.
+//
+// In CSV, the first row labels the upcard columns 2 to 10 and A, in any
+// order, and each following row starts with a label of H for a hard
+// total, S for a soft total or P for a pair, followed by the total or
+// the pair's card:
+//
+//	hand,2,3,4,5,6,7,8,9,10,A
+//	H9,H,Dh,Dh,Dh,Dh,H,H,H,H,H
+//	S18,S,Ds,Ds,Ds,Ds,S,S,H,H,H
+//	PA,P,P,P,P,P,P,P,P,P,P
+//
+// In YAML, rows are listed by total or card under hard, soft and pairs
+// keys, always in upcard order from 2 to A, as flow sequences or
+// space-separated actions. Only this small subset of YAML is supported.
+//
+//	name: my chart
+//	hard:
+//	  9: [H, Dh, Dh, Dh, Dh, H, H, H, H, H]
+//	soft:
+//	  18: S Ds Ds Ds Ds S S H H H
+//	pairs:
+//	  A: [P, P, P, P, P, P, P, P, P, P]
+
+// Load reads a chart from a .csv, .yaml or .yml file. A chart without
+// a name is named after its file.
+func Load(path string) (*Chart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var c *Chart
+	ext := filepath.Ext(path)
+	switch strings.ToLower(ext) {
+	case ".csv":
+		c, err = ParseCSV(f)
+	case ".yaml", ".yml":
+		c, err = ParseYAML(f)
+	default:
+		return nil, fmt.Errorf("strategy: unknown chart format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if c.Name == "" {
+		c.Name = strings.TrimSuffix(filepath.Base(path), ext)
+	}
+	return c, nil
+}
+
+// ParseCSV reads a chart written as CSV.
+func ParseCSV(r io.Reader) (*Chart, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("strategy: empty chart")
+	}
+
+	header := records[0]
+	if len(header) != Upcards+1 {
+		return nil, fmt.Errorf("strategy: chart has %d upcard columns, want %d", len(header)-1, Upcards)
+	}
+	cols := make([]int, Upcards)
+	seen := make(map[int]bool)
+	for i, label := range header[1:] {
+		col, err := parseUpcard(label)
+		if err != nil {
+			return nil, err
+		}
+		if seen[col] {
+			return nil, fmt.Errorf("strategy: upcard %s appears twice", label)
+		}
+		seen[col] = true
+		cols[i] = col
+	}
+
+	c := NewChart("")
+	for _, record := range records[1:] {
+		rows, key, err := c.parseLabel(record[0])
+		if err != nil {
+			return nil, err
+		}
+		var row Row
+		for i, field := range record[1:] {
+			if row[cols[i]], err = parseAction(strings.TrimSpace(field)); err != nil {
+				return nil, fmt.Errorf("%w in row %s", err, record[0])
+			}
+		}
+		if err := addRow(rows, key, row, record[0]); err != nil {
+			return nil, err
+		}
+	}
+	return c, c.validate()
+}
+
+// ParseYAML reads a chart written in the subset of YAML described above.
+func ParseYAML(r io.Reader) (*Chart, error) {
+	c := NewChart("")
+	prefix := "" // the row label prefix of the current section
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, err := splitYAML(line)
+		if err != nil {
+			return nil, fmt.Errorf("strategy: line %d: %w", n, err)
+		}
+
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			prefix = ""
+			switch key {
+			case "name":
+				c.Name = value
+			case "hard":
+				prefix = "H"
+			case "soft":
+				prefix = "S"
+			case "pairs":
+				prefix = "P"
+			default:
+				return nil, fmt.Errorf("strategy: line %d: unknown key %q", n, key)
+			}
+			continue
+		}
+
+		if prefix == "" {
+			return nil, fmt.Errorf("strategy: line %d: unexpected indentation", n)
+		}
+		label := prefix + key
+		rows, k, err := c.parseLabel(label)
+		if err != nil {
+			return nil, fmt.Errorf("strategy: line %d: %w", n, err)
+		}
+		row, err := parseYAMLRow(value)
+		if err != nil {
+			return nil, fmt.Errorf("strategy: line %d: %w", n, err)
+		}
+		if err := addRow(rows, k, row, label); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return c, c.validate()
+}
+
+// splitYAML splits a YAML mapping line into its unquoted key and value.
+func splitYAML(line string) (key, value string, err error) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", "", fmt.Errorf("expected key: value, got %q", strings.TrimSpace(line))
+	}
+	return unquote(line[:i]), unquote(line[i+1:]), nil
+}
+
+func unquote(s string) string {
+	return strings.Trim(strings.TrimSpace(s), `"'`)
+}
+
+// parseYAMLRow parses a row written as a flow sequence or as
+// space-separated actions.
+func parseYAMLRow(s string) (Row, error) {
+	var row Row
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		s = strings.Replace(s[1:len(s)-1], ",", " ", -1)
+	}
+	fields := strings.Fields(s)
+	if len(fields) != Upcards {
+		return row, fmt.Errorf("row has %d actions, want %d", len(fields), Upcards)
+	}
+	for i, f := range fields {
+		a, err := parseAction(unquote(f))
+		if err != nil {
+			return row, err
+		}
+		row[i] = a
+	}
+	return row, nil
+}
+
+func addRow(rows map[int]Row, key int, row Row, label string) error {
+	if _, ok := rows[key]; ok {
+		return fmt.Errorf("strategy: row %s appears twice", label)
+	}
+	rows[key] = row
+	return nil
+}
+
+// parseUpcard returns the column index of an upcard label.
+func parseUpcard(label string) (int, error) {
+	label = strings.TrimSpace(label)
+	for i, l := range upcardLabels {
+		if strings.EqualFold(label, l) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("strategy: invalid upcard %q", label)
+}
+
+// parseLabel returns the rows of the chart a row label belongs to and
+// its key in them.
+func (c *Chart) parseLabel(label string) (map[int]Row, int, error) {
+	label = strings.TrimSpace(label)
+	if len(label) < 2 {
+		return nil, 0, fmt.Errorf("strategy: invalid row label %q", label)
+	}
+	var rows map[int]Row
+	switch strings.ToUpper(label[:1]) {
+	case "H":
+		rows = c.Hard
+	case "S":
+		rows = c.Soft
+	case "P":
+		rows = c.Pairs
+		if strings.EqualFold(label[1:], "A") {
+			return rows, 11, nil
+		}
+	default:
+		return nil, 0, fmt.Errorf("strategy: invalid row label %q", label)
+	}
+	key, err := strconv.Atoi(label[1:])
+	if err != nil {
+		return nil, 0, fmt.Errorf("strategy: invalid row label %q", label)
+	}
+	return rows, key, nil
+}
+
+// WriteCSV writes the chart as CSV, with hard totals, soft totals and
+// pairs each in ascending order.
+func (c *Chart) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(append([]string{"hand"}, upcardLabels[:]...))
+	sections := []struct {
+		prefix string
+		rows   map[int]Row
+	}{{"H", c.Hard}, {"S", c.Soft}, {"P", c.Pairs}}
+	for _, s := range sections {
+		for _, key := range sortedKeys(s.rows) {
+			record := []string{rowLabel(s.prefix, key)}
+			for _, a := range s.rows[key] {
+				record = append(record, a.String())
+			}
+			cw.Write(record)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// rowLabel returns the label of a row, e.g. "H16" or "PA".
+func rowLabel(prefix string, key int) string {
+	if prefix == "P" && key == 11 {
+		return "PA"
+	}
+	return prefix + strconv.Itoa(key)
+}
+
+func sortedKeys(rows map[int]Row) []int {
+	keys := make([]int, 0, len(rows))
+	for k := range rows {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
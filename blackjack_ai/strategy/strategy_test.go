@@ -0,0 +1,355 @@
+package strategy
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angusgmorrison/gophercises/blackjack_ai/blackjack"
+	"github.com/angusgmorrison/gophercises/blackjack_ai/counting"
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+func cards(t *testing.T, notation ...string) []deck.Card {
+	t.Helper()
+	hand := make([]deck.Card, len(notation))
+	for i, n := range notation {
+		c, err := deck.ParseCard(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hand[i] = c
+	}
+	return hand
+}
+
+func TestParse(t *testing.T) {
+	csvChart := `hand,A,2,3,4,5,6,7,8,9,10
+H9,H,H,Dh,Dh,Dh,Dh,H,H,H,H
+S18,h,S,Ds,Ds,Ds,Ds,S,S,H,H
+PA,P,P,P,P,P,P,P,P,P,P
+P10,S,S,S,S,S,S,S,S,S,S
+`
+	yamlChart := `# reordered, lower case and abbreviated
+name: test
+hard:
+  9: [H, Dh, Dh, Dh, Dh, H, H, H, H, H]
+soft:
+  "18": S Ds Ds Ds Ds S S H H h
+pairs:
+  A: [P, P, P, P, P, P, P, P, P, P]
+  10: [S, S, S, S, S, S, S, S, S, S]
+`
+	fromCSV, err := ParseCSV(strings.NewReader(csvChart))
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	fromYAML, err := ParseYAML(strings.NewReader(yamlChart))
+	if err != nil {
+		t.Fatalf("ParseYAML: %v", err)
+	}
+	if fromYAML.Name != "test" {
+		t.Errorf("YAML chart named %q, want %q", fromYAML.Name, "test")
+	}
+	fromYAML.Name = ""
+	if !reflect.DeepEqual(fromCSV, fromYAML) {
+		t.Errorf("CSV chart\n%v\ndiffers from YAML chart\n%v", fromCSV, fromYAML)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	const header = "hand,2,3,4,5,6,7,8,9,10,A\n"
+	csvCases := map[string]string{
+		"missing upcard":   "hand,2,3,4,5,6,7,8,9,10\nH9,H,H,H,H,H,H,H,H,H\n",
+		"repeated upcard":  "hand,2,2,4,5,6,7,8,9,10,A\n",
+		"invalid action":   header + "H9,H,H,H,H,H,H,H,H,H,X\n",
+		"invalid label":    header + "X9,H,H,H,H,H,H,H,H,H,H\n",
+		"impossible total": header + "S22,H,H,H,H,H,H,H,H,H,H\n",
+		"repeated row":     header + "H9,H,H,H,H,H,H,H,H,H,H\nH9,H,H,H,H,H,H,H,H,H,H\n",
+	}
+	for name, chart := range csvCases {
+		if _, err := ParseCSV(strings.NewReader(chart)); err == nil {
+			t.Errorf("CSV %s: expected an error", name)
+		}
+	}
+
+	yamlCases := map[string]string{
+		"unknown key":   "splits:\n  A: [P, P, P, P, P, P, P, P, P, P]\n",
+		"short row":     "hard:\n  9: [H, H]\n",
+		"no section":    "  9: [H, H, H, H, H, H, H, H, H, H]\n",
+		"invalid pair":  "pairs:\n  K: [P, P, P, P, P, P, P, P, P, P]\n",
+		"missing colon": "hard\n",
+	}
+	for name, chart := range yamlCases {
+		if _, err := ParseYAML(strings.NewReader(chart)); err == nil {
+			t.Errorf("YAML %s: expected an error", name)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	for _, chart := range []*Chart{S17(), H17()} {
+		var buf bytes.Buffer
+		if err := chart.WriteCSV(&buf); err != nil {
+			t.Fatalf("%s: %v", chart.Name, err)
+		}
+		got, err := ParseCSV(&buf)
+		if err != nil {
+			t.Fatalf("%s: reparsing: %v", chart.Name, err)
+		}
+		got.Name = chart.Name
+		if !reflect.DeepEqual(got, chart) {
+			t.Errorf("%s changed on writing and reparsing", chart.Name)
+		}
+	}
+}
+
+func TestChartAction(t *testing.T) {
+	testCases := []struct {
+		hand   []string
+		upcard string
+		split  bool
+		chart  *Chart
+		want   Action
+	}{
+		{[]string{"10S", "6H"}, "KD", true, S17(), SurrenderOrHit},
+		{[]string{"8S", "8H"}, "KD", true, S17(), Split},
+		{[]string{"8S", "8H"}, "KD", false, S17(), SurrenderOrHit},
+		{[]string{"8S", "8H"}, "AD", true, H17(), SurrenderOrSplit},
+		{[]string{"AS", "7H"}, "2D", true, S17(), Stand},
+		{[]string{"AS", "7H"}, "2D", true, H17(), DoubleOrStand},
+		{[]string{"5S", "5H"}, "9D", true, S17(), DoubleOrHit},
+		{[]string{"KS", "KH"}, "6D", true, S17(), Stand},
+		{[]string{"2S", "3H"}, "6D", true, S17(), Hit},
+		{[]string{"AS", "AH"}, "6D", false, S17(), Hit},
+		{[]string{"9S", "8H", "3C"}, "AD", true, S17(), Stand},
+	}
+	for _, tc := range testCases {
+		got := tc.chart.Action(cards(t, tc.hand...), cards(t, tc.upcard)[0], tc.split)
+		if got != tc.want {
+			t.Errorf("%s: %v against %s (split %t): got %s, want %s",
+				tc.chart.Name, tc.hand, tc.upcard, tc.split, got, tc.want)
+		}
+	}
+}
+
+func TestDecide(t *testing.T) {
+	testCases := []struct {
+		name   string
+		opts   blackjack.Options
+		round  round
+		hand   []string
+		upcard string
+		want   string
+	}{
+		{"surrender", blackjack.Options{LateSurrender: true}, round{hands: 1}, []string{"10S", "6H"}, "10D", "surrender"},
+		{"no surrender", blackjack.Options{}, round{hands: 1}, []string{"10S", "6H"}, "10D", "hit"},
+		{"no surrender after split", blackjack.Options{LateSurrender: true}, round{hands: 2}, []string{"10S", "6H"}, "10D", "hit"},
+		{"double", blackjack.Options{}, round{hands: 1}, []string{"6S", "5H"}, "5D", "double"},
+		{"no double on three cards", blackjack.Options{}, round{hands: 1}, []string{"2S", "4C", "5H"}, "5D", "hit"},
+		{"no double on soft 17", blackjack.Options{DoubleOn: blackjack.Double9To11}, round{hands: 1}, []string{"AS", "6H"}, "5D", "hit"},
+		{"no double after split", blackjack.Options{NoDoubleAfterSplit: true}, round{hands: 2}, []string{"6S", "5H"}, "5D", "hit"},
+		{"split if DAS", blackjack.Options{}, round{hands: 1}, []string{"2S", "2H"}, "2D", "split"},
+		{"hit without DAS", blackjack.Options{NoDoubleAfterSplit: true}, round{hands: 1}, []string{"2S", "2H"}, "2D", "hit"},
+		{"split limit", blackjack.Options{}, round{hands: 4}, []string{"8S", "8H"}, "5D", "stand"},
+		{"stand on split aces", blackjack.Options{}, round{hands: 2, splitAces: true}, []string{"AS", "5H"}, "5D", "stand"},
+		{"resplit aces", blackjack.Options{ResplitAces: true}, round{hands: 2, splitAces: true}, []string{"AS", "AH"}, "5D", "split"},
+		{"hit split aces", blackjack.Options{HitSplitAces: true}, round{hands: 2, splitAces: true}, []string{"AS", "5H"}, "5D", "double"},
+	}
+	for _, tc := range testCases {
+		upcard := cards(t, tc.upcard)[0]
+		move, _ := newTable(tc.opts).decide(S17(), tc.round, cards(t, tc.hand...), upcard)
+		if got := moveName(move); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+// standAI stands on every hand.
+type standAI struct{}
+
+func (standAI) Bet(shuffled bool) int                                  { return 100 }
+func (standAI) Play(hand []deck.Card, dealer deck.Card) blackjack.Move { return blackjack.MoveStand }
+func (standAI) Outcome(hand [][]deck.Card, dealer []deck.Card)         {}
+
+func TestChecker(t *testing.T) {
+	rules := []blackjack.Options{
+		{},
+		{DealerStandsSoft17: true, LateSurrender: true},
+		{NoDoubleAfterSplit: true, DoubleOn: blackjack.Double10To11, MaxSplitHands: 2},
+		{HitSplitAces: true, ResplitAces: true, NoHoleCard: true},
+	}
+	for i, opts := range rules {
+		opts.NHands = 2000
+		opts.Seed = blackjack.Seed(int64(i + 1))
+		chart := ChartFor(opts)
+
+		// StrategyAI must only make legal moves and never deviate from
+		// its own chart.
+		g := blackjack.New(opts)
+		checker := NewChecker(NewAI(chart, opts), chart, opts)
+		if _, err := g.Play(checker); err != nil {
+			t.Fatal(err)
+		}
+		report := checker.Report()
+		if report.Decisions == 0 {
+			t.Fatalf("rules %d: no decisions checked", i)
+		}
+		if report.Total() != 0 {
+			var buf bytes.Buffer
+			report.WriteText(&buf)
+			t.Errorf("rules %d: StrategyAI deviated from its chart:\n%s", i, buf.String())
+		}
+
+		g = blackjack.New(opts)
+		checker = NewChecker(standAI{}, chart, opts)
+		if _, err := g.Play(checker); err != nil {
+			t.Fatal(err)
+		}
+		report = checker.Report()
+		if rate := report.Rate(); rate <= 0 || rate >= 1 {
+			t.Errorf("rules %d: a player who always stands deviated on %.2f%% of decisions", i, 100*rate)
+		}
+		for _, d := range report.Deviations {
+			if d.Got != "stand" {
+				t.Errorf("rules %d: recorded %s, want stand", i, d.Got)
+			}
+		}
+	}
+}
+
+func TestCountingAI(t *testing.T) {
+	opts := blackjack.Options{NDecks: 2, MaxBet: 500, Insurance: true}
+	ai := NewCountingAI(S17(), opts, CountingConfig{
+		System:   counting.HiLo,
+		Indexes:  Illustrious18,
+		InsureAt: IllustriousInsurance,
+	})
+	hand, ten, ace := cards(t, "10S", "6H"), cards(t, "10D")[0], cards(t, "AD")[0]
+
+	if bet := ai.Bet(true); bet != 100 {
+		t.Errorf("bet %d off the top of the shoe, want 100", bet)
+	}
+	ai.Seen(cards(t, "KH")[0])
+	if got := moveName(ai.Play(hand, ten)); got != "hit" {
+		t.Errorf("played 16 against 10 at a negative count: got %s, want hit", got)
+	}
+	if ai.Insurance(hand, ace) {
+		t.Error("took insurance at a negative count")
+	}
+
+	// Seeing 26 more low cards raises the true count to about +17.
+	for _, c := range cards(t, strings.Fields(strings.Repeat("2S 3H ", 13))...) {
+		ai.Seen(c)
+	}
+	if bet := ai.Bet(false); bet != 500 {
+		t.Errorf("bet %d at a high count, want the table maximum of 500", bet)
+	}
+	if got := moveName(ai.Play(hand, ten)); got != "stand" {
+		t.Errorf("played 16 against 10 at a high count: got %s, want stand", got)
+	}
+	if got := moveName(ai.Play(cards(t, "KS", "KH"), cards(t, "6D")[0])); got != "split" {
+		t.Errorf("played tens against 6 at a high count: got %s, want split", got)
+	}
+	if !ai.Insurance(hand, ace) {
+		t.Error("refused insurance at a high count")
+	}
+
+	if bet := ai.Bet(true); bet != 100 {
+		t.Errorf("bet %d after a shuffle, want 100", bet)
+	}
+}
+
+func TestCountingAIPlaysLegally(t *testing.T) {
+	for i, system := range counting.Systems {
+		opts := blackjack.Options{
+			NHands:        2000,
+			Seed:          blackjack.Seed(int64(i + 1)),
+			Insurance:     true,
+			LateSurrender: true,
+			MaxSplitHands: 3,
+		}
+		ai := NewCountingAI(ChartFor(opts), opts, CountingConfig{System: system, Indexes: Illustrious18})
+		g := blackjack.New(opts)
+		if _, err := g.Play(ai); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPlayShortOfChips(t *testing.T) {
+	// With 150 chips, the player can't afford to double or split their
+	// opening bet, so must fall back to the chart's other moves.
+	opts := blackjack.Options{NHands: 500, Seed: blackjack.Seed(1), Bankroll: 150, MinBet: 100}
+	chart := ChartFor(opts)
+	ais := []blackjack.AI{
+		NewAI(chart, opts),
+		NewCountingAI(chart, opts, CountingConfig{System: counting.HiLo, Indexes: Illustrious18}),
+	}
+	for _, ai := range ais {
+		g := blackjack.New(opts)
+		if _, err := g.Play(ai); err != nil {
+			t.Errorf("%T: %v", ai, err)
+		}
+	}
+}
+
+func TestCheckerRejectedMove(t *testing.T) {
+	// With 150 chips, the player can't afford to split eights against a
+	// ten, so hits 16 instead. The checker must take back the rejected
+	// split rather than check the hit against a split hand.
+	opts := blackjack.Options{
+		NDecks:   1,
+		NHands:   1,
+		Bankroll: 150,
+		Shuffle:  deck.Stack("8S 10H 8D 7C 5D"),
+	}
+	chart := ChartFor(opts)
+	checker := NewChecker(NewAI(chart, opts), chart, opts)
+	g := blackjack.New(opts)
+	if _, err := g.Play(checker); err != nil {
+		t.Fatal(err)
+	}
+	if r := checker.Report(); r.Decisions != 2 || r.Total() != 0 {
+		var buf bytes.Buffer
+		r.WriteText(&buf)
+		t.Errorf("checked a rejected split and a hit as:\n%s", buf.String())
+	}
+}
+
+func TestCountingAIKelly(t *testing.T) {
+	opts := blackjack.Options{NDecks: 2, Bankroll: 10000}
+	ai := NewCountingAI(S17(), opts, CountingConfig{System: counting.HiLo, Kelly: 1})
+
+	if bet := ai.Bet(true); bet != 100 {
+		t.Errorf("bet %d off the top of the shoe, want the table minimum", bet)
+	}
+	// Seeing 26 low cards raises the true count to about +17, an edge of
+	// 8%, for which the Kelly bet is 6% of the bankroll.
+	for _, c := range cards(t, strings.Fields(strings.Repeat("2S 3H ", 13))...) {
+		ai.Seen(c)
+	}
+	if bet := ai.Bet(false); bet != 600 {
+		t.Errorf("bet %d of 10000 chips at a high count, want 600", bet)
+	}
+	ai.Result(blackjack.Result{Net: -5000})
+	if bet := ai.Bet(false); bet != 300 {
+		t.Errorf("bet %d of 5000 chips at a high count, want 300", bet)
+	}
+}
+
+func TestRejectedBet(t *testing.T) {
+	// A rejected bet leaves the new round as it was, rather than taking
+	// back a move from the last one.
+	opts := blackjack.Options{MaxBet: 500}
+	ai := NewCountingAI(S17(), opts, CountingConfig{System: counting.HiLo})
+	ai.Bet(true)
+	ai.Play(cards(t, "8S", "8D"), cards(t, "6H")[0])
+	ai.Bet(false)
+	ai.Rejected(fmt.Errorf("%w: too big", blackjack.ErrInvalidBet))
+	if ai.round != (round{hands: 1}) {
+		t.Errorf("round is %+v after a rejected bet, want a fresh round", ai.round)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -109,8 +110,48 @@ var shuffleRand = rand.New(rand.NewSource(time.Now().UnixNano()))
 
 // Shuffle is an Option returning a randomly shuffled deck of cards using the Fisher-Yates shuffle.
 func Shuffle(cards []Card) []Card {
+	return shuffle(cards, shuffleRand)
+}
+
+// ShuffleWith returns an Option that shuffles a deck using the Fisher-Yates shuffle, drawing its
+// randomness from src. Shuffles made with sources seeded identically produce identical decks.
+func ShuffleWith(src rand.Source) Option {
+	r := rand.New(src)
+	return func(cards []Card) []Card {
+		return shuffle(cards, r)
+	}
+}
+
+// SeededShuffle returns an Option that shuffles a deck reproducibly from the given seed.
+func SeededShuffle(seed int64) Option {
+	return ShuffleWith(rand.NewSource(seed))
+}
+
+// Stack rigs a shoe for tests and demonstrations, for APIs that supply their own source of
+// randomness, such as blackjack's Options.Shuffle. The returned function ignores src and makes an
+// Option that places the cards in top, written in short notation and separated by spaces, on top
+// of the deck in order, leaving the rest unshuffled. Stack panics if top isn't valid notation:
+//
+//	Shuffle: deck.Stack("AS 10H KD 7C")
+func Stack(top string) func(src rand.Source) Option {
+	var stacked []Card
+	for _, s := range strings.Fields(top) {
+		c, err := ParseCard(s)
+		if err != nil {
+			panic(fmt.Sprintf("deck: stack: %v", err))
+		}
+		stacked = append(stacked, c)
+	}
+	return func(rand.Source) Option {
+		return func(cards []Card) []Card {
+			return append(append([]Card(nil), stacked...), cards...)
+		}
+	}
+}
+
+func shuffle(cards []Card, r *rand.Rand) []Card {
 	for i := len(cards) - 1; i > 0; i-- {
-		swapTo := shuffleRand.Intn(i + 1)
+		swapTo := r.Intn(i + 1)
 		cards[i], cards[swapTo] = cards[swapTo], cards[i]
 	}
 	// Returning cards despite the in-place change allows Shuffle to work as an Option.
@@ -78,6 +78,55 @@ func TestShuffle(t *testing.T) {
 	shuffleRand = oldRand
 }
 
+func TestShuffleWith(t *testing.T) {
+	// ShuffleWith must produce the same order as Shuffle for a source with the same seed.
+	want := []Card{
+		{Two, Spades},
+		{Two, Hearts},
+		{Five, Spades},
+	}
+
+	shuffled := New(ShuffleWith(rand.NewSource(0)))
+	for i := 0; i < 3; i++ {
+		if shuffled[i] != want[i] {
+			t.Errorf("card %d is %s, want %s", i+1, shuffled[i], want[i])
+		}
+	}
+}
+
+func TestSeededShuffle(t *testing.T) {
+	a := New(SeededShuffle(42))
+	b := New(SeededShuffle(42))
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("card %d differs between decks shuffled with the same seed: %s, %s", i+1, a[i], b[i])
+		}
+	}
+}
+
+func TestStack(t *testing.T) {
+	want := []Card{{Ace, Spades}, {Ten, Hearts}, {King, Diamonds}}
+	cards := New(Stack("AS 10H KD")(rand.NewSource(0)))
+	if len(cards) != 55 {
+		t.Fatalf("stacked deck has %d cards, want 55", len(cards))
+	}
+	for i := range want {
+		if cards[i] != want[i] {
+			t.Errorf("card %d is %s, want %s", i+1, cards[i], want[i])
+		}
+	}
+	if unshuffled := New(); cards[3] != unshuffled[0] {
+		t.Errorf("card 4 is %s, want the rest of the deck unshuffled from %s", cards[3], unshuffled[0])
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("stacked invalid notation without panicking")
+		}
+	}()
+	Stack("AS XX")
+}
+
 func TestJokers(t *testing.T) {
 	wantJokers := 3
 	cards := New(Jokers(wantJokers))
@@ -0,0 +1,200 @@
+package deck
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Cards, suits and ranks implement encoding.TextMarshaler and encoding.TextUnmarshaler, so they are
+// encoded as strings by encoding/json and encoding/xml and may be used as JSON object keys.
+//
+// Cards are written in short notation: a rank of A, 2-10, J, Q or K followed by a suit of S, D,
+// C or H, e.g. "AS" or "10H". Jokers are written as "Joker", followed by their rank if it is
+// non-zero, e.g. "Joker2".
+
+const jokerNotation = "Joker"
+
+var rankNotation = [...]string{
+	Ace:   "A",
+	Two:   "2",
+	Three: "3",
+	Four:  "4",
+	Five:  "5",
+	Six:   "6",
+	Seven: "7",
+	Eight: "8",
+	Nine:  "9",
+	Ten:   "10",
+	Jack:  "J",
+	Queen: "Q",
+	King:  "K",
+}
+
+var suitNotation = [...]string{
+	Spades:   "S",
+	Diamonds: "D",
+	Clubs:    "C",
+	Hearts:   "H",
+}
+
+// ErrInvalidCard is returned when text or bytes do not describe a valid card.
+var ErrInvalidCard = errors.New("deck: invalid card")
+
+// ShortString returns the card in short notation, e.g. "AS" for the Ace of Spades.
+func (c Card) ShortString() string {
+	if c.Suit == Joker {
+		if c.Rank == 0 {
+			return jokerNotation
+		}
+		return jokerNotation + strconv.Itoa(int(c.Rank))
+	}
+	if !c.valid() {
+		return c.String()
+	}
+	return rankNotation[c.Rank] + suitNotation[c.Suit]
+}
+
+func (c Card) valid() bool {
+	if c.Suit == Joker {
+		return true
+	}
+	return c.Suit < Joker && c.Rank >= minRank && c.Rank <= maxRank
+}
+
+// ParseCard parses a card written in short notation. Ranks and suits are case-insensitive, and
+// "T" is accepted for Ten.
+func ParseCard(s string) (Card, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= len(jokerNotation) && strings.EqualFold(s[:len(jokerNotation)], jokerNotation) {
+		return parseJoker(s[len(jokerNotation):])
+	}
+	if len(s) < 2 {
+		return Card{}, fmt.Errorf("%w %q", ErrInvalidCard, s)
+	}
+	rank, err := parseRank(s[:len(s)-1])
+	if err != nil {
+		return Card{}, fmt.Errorf("%w %q", ErrInvalidCard, s)
+	}
+	suit, err := parseSuit(s[len(s)-1:])
+	if err != nil {
+		return Card{}, fmt.Errorf("%w %q", ErrInvalidCard, s)
+	}
+	return Card{Rank: rank, Suit: suit}, nil
+}
+
+func parseJoker(rank string) (Card, error) {
+	if rank == "" {
+		return Card{Suit: Joker}, nil
+	}
+	n, err := strconv.ParseUint(rank, 10, 8)
+	if err != nil {
+		return Card{}, fmt.Errorf("%w %q", ErrInvalidCard, jokerNotation+rank)
+	}
+	return Card{Rank: Rank(n), Suit: Joker}, nil
+}
+
+// parseRank parses a rank from its short notation or its name.
+func parseRank(s string) (Rank, error) {
+	if strings.EqualFold(s, "T") {
+		return Ten, nil
+	}
+	for r := minRank; r <= maxRank; r++ {
+		if strings.EqualFold(s, rankNotation[r]) || strings.EqualFold(s, r.String()) {
+			return r, nil
+		}
+	}
+	return 0, fmt.Errorf("deck: invalid rank %q", s)
+}
+
+// parseSuit parses a suit from its short notation or its name.
+func parseSuit(s string) (Suit, error) {
+	for _, suit := range suits {
+		if strings.EqualFold(s, suitNotation[suit]) || strings.EqualFold(s, suit.String()) {
+			return suit, nil
+		}
+	}
+	if strings.EqualFold(s, Joker.String()) {
+		return Joker, nil
+	}
+	return 0, fmt.Errorf("deck: invalid suit %q", s)
+}
+
+// MarshalText implements encoding.TextMarshaler, writing the card in short notation.
+func (c Card) MarshalText() ([]byte, error) {
+	if !c.valid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCard, c)
+	}
+	return []byte(c.ShortString()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, reading a card in short notation.
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding the card as its rank followed by
+// its suit.
+func (c Card) MarshalBinary() ([]byte, error) {
+	if !c.valid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCard, c)
+	}
+	return []byte{byte(c.Rank), byte(c.Suit)}, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *Card) UnmarshalBinary(data []byte) error {
+	if len(data) != 2 {
+		return fmt.Errorf("%w: want 2 bytes, got %d", ErrInvalidCard, len(data))
+	}
+	card := Card{Rank: Rank(data[0]), Suit: Suit(data[1])}
+	if !card.valid() {
+		return fmt.Errorf("%w: %s", ErrInvalidCard, card)
+	}
+	*c = card
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, writing the suit's name.
+func (s Suit) MarshalText() ([]byte, error) {
+	if s > Joker {
+		return nil, fmt.Errorf("deck: invalid suit %d", s)
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting a suit's name or its short
+// notation.
+func (s *Suit) UnmarshalText(text []byte) error {
+	suit, err := parseSuit(string(text))
+	if err != nil {
+		return err
+	}
+	*s = suit
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, writing the rank's name.
+func (r Rank) MarshalText() ([]byte, error) {
+	if r < minRank || r > maxRank {
+		return nil, fmt.Errorf("deck: invalid rank %d", r)
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting a rank's name or its short
+// notation.
+func (r *Rank) UnmarshalText(text []byte) error {
+	rank, err := parseRank(string(text))
+	if err != nil {
+		return err
+	}
+	*r = rank
+	return nil
+}
@@ -0,0 +1,95 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func ExampleParseCard() {
+	for _, s := range []string{"AS", "10h", "td", "qC", "Joker", "Joker2"} {
+		card, err := ParseCard(s)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(card.ShortString(), card)
+	}
+
+	// Output:
+	// AS Ace of Spades
+	// 10H Ten of Hearts
+	// 10D Ten of Diamonds
+	// QC Queen of Clubs
+	// Joker Joker
+	// Joker2 Joker
+}
+
+func TestParseCardInvalid(t *testing.T) {
+	for _, s := range []string{"", "A", "1S", "11S", "AX", "JokerX", "S"} {
+		if card, err := ParseCard(s); err == nil {
+			t.Errorf("ParseCard(%q) = %s, want error", s, card)
+		}
+	}
+}
+
+func TestCardTextRoundTrip(t *testing.T) {
+	for _, want := range New(Jokers(3)) {
+		text, err := want.MarshalText()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", want, err)
+		}
+		var got Card
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("%s: unexpected error: %v", want, err)
+		}
+		if got != want {
+			t.Errorf("%q unmarshalled to %#v, want %#v", text, got, want)
+		}
+	}
+}
+
+func TestCardBinaryRoundTrip(t *testing.T) {
+	for _, want := range New(Jokers(3)) {
+		data, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", want, err)
+		}
+		var got Card
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%s: unexpected error: %v", want, err)
+		}
+		if got != want {
+			t.Errorf("%v unmarshalled to %#v, want %#v", data, got, want)
+		}
+	}
+}
+
+func TestCardJSON(t *testing.T) {
+	type hand struct {
+		Cards []Card
+		Trump Suit
+		Wild  Rank
+	}
+	want := hand{
+		Cards: []Card{{Ace, Spades}, {Ten, Hearts}, {Rank: 1, Suit: Joker}},
+		Trump: Clubs,
+		Wild:  Two,
+	}
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantJSON := `{"Cards":["AS","10H","Joker1"],"Trump":"Clubs","Wild":"Two"}`
+	if string(data) != wantJSON {
+		t.Errorf("got JSON %s, want %s", data, wantJSON)
+	}
+
+	var got hand
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package deck
+
+import "fmt"
+
+// Marked is a card marked with the physical deck it came from, as if by the colour of its back,
+// so that the copies of a card in a shoe of several decks can be told apart.
+type Marked struct {
+	Card
+	Deck int
+}
+
+func (m Marked) String() string {
+	return fmt.Sprintf("%s (deck %d)", m.Card, m.Deck)
+}
+
+// Mark marks the copies of each card in cards with the deck they came from, in the order they
+// appear: the first copy is marked deck 0, the second deck 1, and so on. Mark a deck returned by
+// New with the Deck Option before shuffling it.
+func Mark(cards []Card) []Marked {
+	seen := make(map[Card]int)
+	ret := make([]Marked, len(cards))
+	for i, card := range cards {
+		ret[i] = Marked{Card: card, Deck: seen[card]}
+		seen[card]++
+	}
+	return ret
+}
+
+// Apply applies the Options to marked cards, keeping each card's mark. Copies of a card keep
+// their order relative to one another, which is indistinguishable from any other order when the
+// Options shuffle. Cards the Options add, such as Jokers, are marked deck 0.
+func Apply(cards []Marked, opts ...Option) []Marked {
+	plain := Unmark(cards)
+	for _, opt := range opts {
+		plain = opt(plain)
+	}
+	decks := make(map[Card][]int)
+	for _, m := range cards {
+		decks[m.Card] = append(decks[m.Card], m.Deck)
+	}
+	ret := make([]Marked, len(plain))
+	for i, card := range plain {
+		ret[i].Card = card
+		if d := decks[card]; len(d) > 0 {
+			ret[i].Deck, decks[card] = d[0], d[1:]
+		}
+	}
+	return ret
+}
+
+// Unmark returns the cards without their marks.
+func Unmark(cards []Marked) []Card {
+	ret := make([]Card, len(cards))
+	for i, m := range cards {
+		ret[i] = m.Card
+	}
+	return ret
+}
+
+// Duplicates returns every marked card that appears in cards more than once, in the order each
+// card's second copy appears. A shoe of marked decks should have none.
+func Duplicates(cards []Marked) []Marked {
+	seen := make(map[Marked]int)
+	var ret []Marked
+	for _, m := range cards {
+		seen[m]++
+		if seen[m] == 2 {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,40 @@
+package deck
+
+import "testing"
+
+func TestMark(t *testing.T) {
+	cards := Mark(New(Deck(3)))
+	for i, m := range cards {
+		if want := i / cardsInDeck; m.Deck != want {
+			t.Fatalf("card %d, %s, is marked deck %d, want %d", i+1, m.Card, m.Deck, want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	cards := Apply(Mark(New(Deck(2))), SeededShuffle(1), Filter(func(c Card) bool { return c.Suit == Spades }), DefaultSort, Jokers(1))
+	if want := 2*(cardsInDeck-13) + 1; len(cards) != want {
+		t.Fatalf("got %d cards, want %d", len(cards), want)
+	}
+	for i := 0; i+1 < len(cards)-1; i += 2 {
+		a, b := cards[i], cards[i+1]
+		if a.Card != b.Card || a.Deck != 0 || b.Deck != 1 {
+			t.Fatalf("cards %d and %d are %s and %s, want copies from decks 0 and 1", i+1, i+2, a, b)
+		}
+	}
+	if joker := cards[len(cards)-1]; joker.Suit != Joker || joker.Deck != 0 {
+		t.Errorf("last card is %s, want a Joker from deck 0", joker)
+	}
+	if dups := Duplicates(cards); len(dups) != 0 {
+		t.Errorf("marked shoe has duplicates %v", dups)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	aceOfSpades := Marked{Card: Card{Rank: Ace, Suit: Spades}, Deck: 1}
+	cards := append(Mark(New(Deck(2))), aceOfSpades, aceOfSpades)
+	dups := Duplicates(cards)
+	if len(dups) != 1 || dups[0] != aceOfSpades {
+		t.Errorf("got duplicates %v, want [%s]", dups, aceOfSpades)
+	}
+}
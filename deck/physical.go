@@ -0,0 +1,139 @@
+package deck
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Physical shuffles model the imperfect ways a dealer shuffles by hand. Unlike Shuffle, a few of
+// them leave cards correlated with their original positions, which matters when studying how
+// shuffle quality affects games such as blackjack.
+
+// Parameters of the physical shuffle models.
+const (
+	// stripPackets is the average number of packets a deck is split into by a strip cut.
+	stripPackets = 5
+	// overhandBreak is the chance that an overhand shuffle splits the deck between two cards,
+	// giving packets of about eight cards on average.
+	overhandBreak = 1.0 / 8
+)
+
+// A Shuffler performs physical shuffles using randomness drawn from a single source, so that a
+// sequence of shuffles can be reproduced from its seed.
+type Shuffler struct {
+	r *rand.Rand
+}
+
+// NewShuffler returns a Shuffler drawing its randomness from src.
+func NewShuffler(src rand.Source) *Shuffler {
+	return &Shuffler{r: rand.New(src)}
+}
+
+// WithShuffler adapts shuffles made by a Shuffler to APIs that supply their own source of
+// randomness, such as blackjack's Options.Shuffle. The returned function makes an Option by
+// calling shuffle with a new Shuffler drawing from src, so every source gets its own Shuffler:
+//
+//	Shuffle: deck.WithShuffler(func(s *deck.Shuffler) deck.Option { return s.Riffle(7) })
+func WithShuffler(shuffle func(s *Shuffler) Option) func(src rand.Source) Option {
+	return func(src rand.Source) Option {
+		return shuffle(NewShuffler(src))
+	}
+}
+
+// defaultShuffler returns a Shuffler with its own source, seeded from the clock, so that the
+// Options made by Riffle, StripCut and Overhand may be used on different goroutines.
+func defaultShuffler() *Shuffler {
+	return NewShuffler(rand.NewSource(time.Now().UnixNano()))
+}
+
+// Riffle returns an Option that riffle shuffles a deck n times.
+func Riffle(n int) Option {
+	return defaultShuffler().Riffle(n)
+}
+
+// StripCut returns an Option that strip cuts a deck n times.
+func StripCut(n int) Option {
+	return defaultShuffler().StripCut(n)
+}
+
+// Overhand returns an Option that overhand shuffles a deck n times.
+func Overhand(n int) Option {
+	return defaultShuffler().Overhand(n)
+}
+
+// Riffle returns an Option that riffle shuffles a deck n times, following the Gilbert-Shannon-Reeds
+// model: the deck is cut into two packets at a binomially distributed point, then cards are
+// dropped from the bottom of each packet with probability proportional to the packet's size.
+func (s *Shuffler) Riffle(n int) Option {
+	return func(cards []Card) []Card {
+		for i := 0; i < n; i++ {
+			s.riffle(cards)
+		}
+		return cards
+	}
+}
+
+func (s *Shuffler) riffle(cards []Card) {
+	cut := 0
+	for range cards {
+		cut += s.r.Intn(2)
+	}
+	left := append([]Card(nil), cards[:cut]...)
+	right := append([]Card(nil), cards[cut:]...)
+	for i := range cards {
+		if s.r.Intn(len(left)+len(right)) < len(left) {
+			cards[i], left = left[0], left[1:]
+		} else {
+			cards[i], right = right[0], right[1:]
+		}
+	}
+}
+
+// StripCut returns an Option that strip cuts a deck n times: packets of random size are pulled from
+// the top of the deck one by one and dropped onto a new pile, reversing the order of the packets
+// while preserving the order of the cards within them.
+func (s *Shuffler) StripCut(n int) Option {
+	return func(cards []Card) []Card {
+		for i := 0; i < n; i++ {
+			s.reversePackets(cards, func(remaining int) int {
+				return 1 + s.r.Intn(2*len(cards)/stripPackets+1)
+			})
+		}
+		return cards
+	}
+}
+
+// Overhand returns an Option that overhand shuffles a deck n times. Following Pemantle's model,
+// the deck is split between each pair of adjacent cards with a fixed probability, and the
+// resulting packets are reversed in order.
+func (s *Shuffler) Overhand(n int) Option {
+	return func(cards []Card) []Card {
+		for i := 0; i < n; i++ {
+			s.reversePackets(cards, func(remaining int) int {
+				size := 1
+				for size < remaining && s.r.Float64() >= overhandBreak {
+					size++
+				}
+				return size
+			})
+		}
+		return cards
+	}
+}
+
+// reversePackets splits cards into consecutive packets with sizes chosen by packetSize, given the
+// number of cards not yet in a packet, and reverses the order of the packets in place.
+func (s *Shuffler) reversePackets(cards []Card, packetSize func(remaining int) int) {
+	pile := make([]Card, len(cards))
+	top := len(pile)
+	for i := 0; i < len(cards); {
+		size := packetSize(len(cards) - i)
+		if size > len(cards)-i {
+			size = len(cards) - i
+		}
+		top -= size
+		copy(pile[top:], cards[i:i+size])
+		i += size
+	}
+	copy(cards, pile)
+}
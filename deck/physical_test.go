@@ -0,0 +1,89 @@
+package deck
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPhysicalShuffles(t *testing.T) {
+	s := NewShuffler(rand.NewSource(0))
+	testCases := []struct {
+		name string
+		opt  Option
+	}{
+		{"riffle", s.Riffle(7)},
+		{"strip cut", s.StripCut(4)},
+		{"overhand", s.Overhand(10)},
+		{"default riffle", Riffle(1)},
+	}
+	for _, tc := range testCases {
+		cards := New(Deck(2), tc.opt)
+		if len(cards) != 2*cardsInDeck {
+			t.Errorf("%s: deck has len %d, want %d", tc.name, len(cards), 2*cardsInDeck)
+			continue
+		}
+		counts := make(map[Card]int)
+		for _, card := range cards {
+			counts[card]++
+		}
+		for card, n := range counts {
+			if n != 2 {
+				t.Errorf("%s: found %d of %s, want 2", tc.name, n, card)
+			}
+		}
+		if len(counts) != cardsInDeck {
+			t.Errorf("%s: found %d distinct cards, want %d", tc.name, len(counts), cardsInDeck)
+		}
+	}
+}
+
+func TestRifflePreservesPackets(t *testing.T) {
+	// A single riffle interleaves two packets, each of which keeps its original order, so the deck
+	// is made of at most two rising sequences: runs of consecutive cards from the original deck
+	// that remain in order.
+	cards := New(NewShuffler(rand.NewSource(1)).Riffle(1))
+	pos := make(map[Card]int)
+	for i, card := range cards {
+		pos[card] = i
+	}
+	original := New()
+	rising := 1
+	for i := 1; i < len(original); i++ {
+		if pos[original[i]] < pos[original[i-1]] {
+			rising++
+		}
+	}
+	if rising > 2 {
+		t.Errorf("single riffle produced %d rising sequences, want at most 2", rising)
+	}
+}
+
+func TestStripCutReversesPackets(t *testing.T) {
+	// The packet taken from the top of the deck is dropped first, so it ends up at the bottom with
+	// its cards still in order.
+	cards := New(NewShuffler(rand.NewSource(0)).StripCut(1))
+	original := New()
+	var i int
+	for i = range cards {
+		if cards[i] == original[0] {
+			break
+		}
+	}
+	for j := 0; i+j < len(cards); j++ {
+		if cards[i+j] != original[j] {
+			t.Fatalf("card %d is %s, want %s from the original top packet", i+j+1, cards[i+j], original[j])
+		}
+	}
+}
+
+func TestWithShuffler(t *testing.T) {
+	// Each source gets its own Shuffler, so equally seeded sources shuffle alike.
+	riffle := WithShuffler(func(s *Shuffler) Option { return s.Riffle(3) })
+	a := New(riffle(rand.NewSource(7)))
+	b := New(riffle(rand.NewSource(7)))
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("card %d is %s and %s from equally seeded sources", i+1, a[i], b[i])
+		}
+	}
+}
@@ -0,0 +1,423 @@
+//go:generate stringer -type=Category
+
+// Package poker evaluates poker hands made from cards in package deck.
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// Category is the class of a poker hand, from HighCard up to FiveOfAKind. Categories are ordered,
+// so a greater Category beats a lesser one.
+type Category uint8
+
+const (
+	HighCard Category = iota
+	OnePair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+	FiveOfAKind // only possible with wild cards
+)
+
+// Hand is the evaluation of the best five-card poker hand that can be made from a set of cards.
+type Hand struct {
+	Category Category
+	// Ranks holds the ranks that break ties between hands of the same Category, most significant
+	// first: e.g. the trips then the pair of a full house, or the top card of a straight. Unused
+	// entries are zero.
+	Ranks [5]deck.Rank
+	// Value orders hands: a hand with a greater Value beats one with a lesser Value, and hands
+	// with equal Values split the pot.
+	Value uint32
+}
+
+func (h Hand) String() string {
+	return fmt.Sprintf("%s (%s high)", h.Category, h.Ranks[0])
+}
+
+// Compare returns 1 if a beats b, -1 if b beats a, and 0 if they tie.
+func Compare(a, b Hand) int {
+	switch {
+	case a.Value > b.Value:
+		return 1
+	case a.Value < b.Value:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// Hands are evaluated from 5 to 7 cards.
+const (
+	minCards = 5
+	maxCards = 7
+)
+
+var (
+	// ErrHandSize is returned when evaluating fewer than 5 or more than 7 cards.
+	ErrHandSize = errors.New("poker: hands must have between 5 and 7 cards")
+	// ErrJoker is returned when evaluating a hand containing a Joker without wild cards.
+	ErrJoker = errors.New("poker: Jokers are only allowed when wild")
+	// ErrInvalidCard is returned when evaluating a card with a rank or suit that doesn't exist.
+	ErrInvalidCard = errors.New("poker: invalid card")
+)
+
+// Evaluate returns the best five-card hand that can be made from cards. Jokers are not
+// allowed.
+func Evaluate(cards ...deck.Card) (Hand, error) {
+	if len(cards) < minCards || len(cards) > maxCards {
+		return Hand{}, ErrHandSize
+	}
+	var t tally
+	for _, c := range cards {
+		if c.Suit == deck.Joker {
+			return Hand{}, ErrJoker
+		}
+		if !valid(c) {
+			return Hand{}, ErrInvalidCard
+		}
+		t.add(c)
+	}
+	return t.evaluate(), nil
+}
+
+// EvaluateWild returns the best five-card hand that can be made from cards, with each Joker
+// standing in for whichever card makes the best hand. Jokers may duplicate other cards, so five
+// of a kind is possible.
+func EvaluateWild(cards ...deck.Card) (Hand, error) {
+	if len(cards) < minCards || len(cards) > maxCards {
+		return Hand{}, ErrHandSize
+	}
+	var t tally
+	var jokers int
+	for _, c := range cards {
+		if c.Suit == deck.Joker {
+			jokers++
+			continue
+		}
+		if !valid(c) {
+			return Hand{}, ErrInvalidCard
+		}
+		t.add(c)
+	}
+	return t.evaluateWild(jokers), nil
+}
+
+// valid reports whether c is one of the 52 cards of a standard deck.
+func valid(c deck.Card) bool {
+	return c.Suit <= deck.Hearts && c.Rank >= deck.Ace && c.Rank <= deck.King
+}
+
+// nRanks is the number of ranks in a suit. Within this package, ranks are indexed from 0 for
+// Two up to 12 for Ace, so that aces rank high.
+const nRanks = 13
+
+func rankIndex(r deck.Rank) int {
+	if r == deck.Ace {
+		return nRanks - 1
+	}
+	return int(r) - 2
+}
+
+func rankFromIndex(i int) deck.Rank {
+	if i == nRanks-1 {
+		return deck.Ace
+	}
+	return deck.Rank(i + 2)
+}
+
+var pokerSuits = [...]deck.Suit{deck.Spades, deck.Diamonds, deck.Clubs, deck.Hearts}
+
+// tally counts the cards in a hand by rank and by suit, which is all that is needed to evaluate
+// it.
+type tally struct {
+	counts [nRanks]uint8
+	suits  [len(pokerSuits)]uint16 // bit i is set if the suit holds rank index i
+}
+
+func (t *tally) add(c deck.Card) {
+	i := rankIndex(c.Rank)
+	t.counts[i]++
+	t.suits[c.Suit] |= 1 << uint(i)
+}
+
+// evaluateWild returns the best hand the jokers can make with the tallied cards. Jokers may
+// duplicate other cards, so only the ranks and suits a category needs matter: each category is
+// tried from the best down, spending jokers on the cards missing from its best ranks, and the
+// first that can be made is the best hand.
+func (t *tally) evaluateWild(jokers int) Hand {
+	if jokers == 0 {
+		return t.evaluate()
+	}
+	var union uint16
+	for _, m := range t.suits {
+		union |= m
+	}
+
+	if i := t.bestOfAKind(5, jokers); i >= 0 {
+		b := newHand(FiveOfAKind)
+		b.add(i)
+		return b.h
+	}
+
+	// Straight flushes, and the best flush, filled with the highest ranks missing from the suit.
+	straightFlush, flush := -1, uint16(0)
+	for _, m := range t.suits {
+		if high := wildStraightHigh(m, jokers); high > straightFlush {
+			straightFlush = high
+		}
+		if bits.OnesCount16(m)+jokers < minCards {
+			continue
+		}
+		filled := m
+		for k := 0; k < jokers; k++ {
+			filled |= 1 << uint(top(allRanks&^filled))
+		}
+		if filled = topN(filled, 5); filled > flush {
+			flush = filled
+		}
+	}
+	if straightFlush >= 0 {
+		b := newHand(StraightFlush)
+		b.add(straightFlush)
+		return b.h
+	}
+
+	if quads := t.bestOfAKind(4, jokers); quads >= 0 {
+		// Any jokers left over would have made five of a kind, so the kicker is a real card.
+		b := newHand(FourOfAKind)
+		b.add(quads)
+		b.addTop(without(union, quads), 1)
+		return b.h
+	}
+	for trips := nRanks - 1; trips >= 0; trips-- {
+		for pair := nRanks - 1; pair >= 0; pair-- {
+			if pair != trips && short(t.counts[trips], 3)+short(t.counts[pair], 2) <= jokers {
+				b := newHand(FullHouse)
+				b.add(trips)
+				b.add(pair)
+				return b.h
+			}
+		}
+	}
+	if flush != 0 {
+		b := newHand(Flush)
+		b.addTop(flush, 5)
+		return b.h
+	}
+	if high := wildStraightHigh(union, jokers); high >= 0 {
+		b := newHand(Straight)
+		b.add(high)
+		return b.h
+	}
+	// A joker makes trips of any pair, so two pair is never the best hand.
+	if trips := t.bestOfAKind(3, jokers); trips >= 0 {
+		b := newHand(ThreeOfAKind)
+		b.add(trips)
+		b.addTop(without(union, trips), 2)
+		return b.h
+	}
+	b := newHand(OnePair)
+	pair := top(union)
+	b.add(pair)
+	b.addTop(without(union, pair), 3)
+	return b.h
+}
+
+// allRanks is the rank mask holding every rank.
+const allRanks = 1<<nRanks - 1
+
+// bestOfAKind returns the highest rank index of which jokers can make n of a kind, or -1 if
+// there is none.
+func (t *tally) bestOfAKind(n, jokers int) int {
+	for i := nRanks - 1; i >= 0; i-- {
+		if short(t.counts[i], n) <= jokers {
+			return i
+		}
+	}
+	return -1
+}
+
+// short returns the number of cards that count is short of n.
+func short(count uint8, n int) int {
+	if int(count) >= n {
+		return 0
+	}
+	return n - int(count)
+}
+
+// wildStraightHigh returns the rank index of the highest card of the best straight that jokers
+// can complete in mask, or -1 if there is none.
+func wildStraightHigh(mask uint16, jokers int) int {
+	for high := nRanks - 1; high >= 4; high-- {
+		run := uint16(0x1f) << uint(high-4)
+		if 5-bits.OnesCount16(mask&run) <= jokers {
+			return high
+		}
+	}
+	if 5-bits.OnesCount16(mask&wheel) <= jokers {
+		return 3 // Five
+	}
+	return -1
+}
+
+// wheel is the rank mask of the five-high straight, A-2-3-4-5.
+const wheel = 1<<12 | 0xf
+
+// straightHigh returns the rank index of the highest card of the best straight in mask, or -1 if
+// there is none.
+func straightHigh(mask uint16) int {
+	for high := nRanks - 1; high >= 4; high-- {
+		run := uint16(0x1f) << uint(high-4)
+		if mask&run == run {
+			return high
+		}
+	}
+	if mask&wheel == wheel {
+		return 3 // Five
+	}
+	return -1
+}
+
+func (t *tally) evaluate() Hand {
+	var union uint16
+	for _, m := range t.suits {
+		union |= m
+	}
+
+	// Masks of the ranks held at least n times.
+	var atLeast [6]uint16
+	for i, n := range t.counts {
+		for k := 1; k <= int(n) && k < len(atLeast); k++ {
+			atLeast[k] |= 1 << uint(i)
+		}
+	}
+
+	if atLeast[5] != 0 {
+		b := newHand(FiveOfAKind)
+		b.add(top(atLeast[5]))
+		return b.h
+	}
+
+	// Straight flushes and flushes.
+	flushSuit := -1
+	for s, m := range t.suits {
+		if bits.OnesCount16(m) < minCards {
+			continue
+		}
+		if high := straightHigh(m); high >= 0 {
+			b := newHand(StraightFlush)
+			b.add(high)
+			return b.h
+		}
+		if flushSuit < 0 || topN(m, 5) > topN(t.suits[flushSuit], 5) {
+			flushSuit = s
+		}
+	}
+
+	if atLeast[4] != 0 {
+		b := newHand(FourOfAKind)
+		quads := top(atLeast[4])
+		b.add(quads)
+		b.addTop(without(atLeast[1], quads), 1)
+		return b.h
+	}
+	if atLeast[3] != 0 {
+		trips := top(atLeast[3])
+		if pairs := without(atLeast[2], trips); pairs != 0 {
+			b := newHand(FullHouse)
+			b.add(trips)
+			b.add(top(pairs))
+			return b.h
+		}
+	}
+	if flushSuit >= 0 {
+		b := newHand(Flush)
+		b.addTop(t.suits[flushSuit], 5)
+		return b.h
+	}
+	if high := straightHigh(union); high >= 0 {
+		b := newHand(Straight)
+		b.add(high)
+		return b.h
+	}
+	if atLeast[3] != 0 {
+		b := newHand(ThreeOfAKind)
+		trips := top(atLeast[3])
+		b.add(trips)
+		b.addTop(without(union, trips), 2)
+		return b.h
+	}
+	if pairs := atLeast[2]; pairs != 0 {
+		high := top(pairs)
+		if rest := without(pairs, high); rest != 0 {
+			b := newHand(TwoPair)
+			low := top(rest)
+			b.add(high)
+			b.add(low)
+			b.addTop(without(without(union, high), low), 1)
+			return b.h
+		}
+		b := newHand(OnePair)
+		b.add(high)
+		b.addTop(without(union, high), 3)
+		return b.h
+	}
+	b := newHand(HighCard)
+	b.addTop(union, 5)
+	return b.h
+}
+
+// builder accumulates the ranks that break ties between hands of the same category.
+type builder struct {
+	h Hand
+	n int
+}
+
+func newHand(c Category) builder {
+	return builder{h: Hand{Category: c, Value: uint32(c) << 20}}
+}
+
+// add appends the rank with index i as the next most significant tie-breaker.
+func (b *builder) add(i int) {
+	b.h.Ranks[b.n] = rankFromIndex(i)
+	// Rank indices are offset by one so that an unused tie-breaker sorts below a Two.
+	b.h.Value |= uint32(i+1) << uint(16-4*b.n)
+	b.n++
+}
+
+// addTop adds the n highest ranks set in mask, highest first.
+func (b *builder) addTop(mask uint16, n int) {
+	for ; n > 0 && mask != 0; n-- {
+		i := top(mask)
+		b.add(i)
+		mask = without(mask, i)
+	}
+}
+
+// top returns the highest rank index in mask.
+func top(mask uint16) int {
+	return bits.Len16(mask) - 1
+}
+
+// without returns mask with rank index i cleared.
+func without(mask uint16, i int) uint16 {
+	return mask &^ (1 << uint(i))
+}
+
+// topN returns a mask of the n highest ranks set in mask.
+func topN(mask uint16, n int) uint16 {
+	for bits.OnesCount16(mask) > n {
+		mask &= mask - 1 // clear the lowest set bit
+	}
+	return mask
+}
@@ -0,0 +1,173 @@
+package poker
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/angusgmorrison/gophercises/deck"
+)
+
+// cards parses a space-separated list of cards in short notation.
+func cards(t testing.TB, s string) []deck.Card {
+	t.Helper()
+	var ret []deck.Card
+	for _, f := range strings.Fields(s) {
+		c, err := deck.ParseCard(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		cards     string
+		wantCat   Category
+		wantRanks [5]deck.Rank
+	}{
+		{"AS KD 9C 7H 3S", HighCard, [5]deck.Rank{deck.Ace, deck.King, deck.Nine, deck.Seven, deck.Three}},
+		{"AS AD 9C 7H 3S", OnePair, [5]deck.Rank{deck.Ace, deck.Nine, deck.Seven, deck.Three}},
+		{"AS AD 9C 9H 3S 3D KC", TwoPair, [5]deck.Rank{deck.Ace, deck.Nine, deck.King}},
+		{"7S 7D 7C KH 3S", ThreeOfAKind, [5]deck.Rank{deck.Seven, deck.King, deck.Three}},
+		{"AS 2D 3C 4H 5S", Straight, [5]deck.Rank{deck.Five}},
+		{"10S JD QC KH AS", Straight, [5]deck.Rank{deck.Ace}},
+		{"2H 9H 4H KH 7H 8H", Flush, [5]deck.Rank{deck.King, deck.Nine, deck.Eight, deck.Seven, deck.Four}},
+		{"7S 7D 7C KH KS", FullHouse, [5]deck.Rank{deck.Seven, deck.King}},
+		{"7S 7D 7C KH KS KC 2D", FullHouse, [5]deck.Rank{deck.King, deck.Seven}},
+		{"7S 7D 7C 7H KS", FourOfAKind, [5]deck.Rank{deck.Seven, deck.King}},
+		{"9C 10C JC QC KC AD 2C", StraightFlush, [5]deck.Rank{deck.King}},
+	}
+	for _, tc := range testCases {
+		h, err := Evaluate(cards(t, tc.cards)...)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.cards, err)
+		}
+		if h.Category != tc.wantCat || h.Ranks != tc.wantRanks {
+			t.Errorf("%s: got %s %v, want %s %v", tc.cards, h.Category, h.Ranks, tc.wantCat, tc.wantRanks)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	if _, err := Evaluate(cards(t, "AS KS QS JS")...); err != ErrHandSize {
+		t.Errorf("got error %v, want %v", err, ErrHandSize)
+	}
+	if _, err := Evaluate(cards(t, "AS KS QS JS Joker")...); err != ErrJoker {
+		t.Errorf("got error %v, want %v", err, ErrJoker)
+	}
+	invalid := append(cards(t, "AS KS QS JS"), deck.Card{Suit: deck.Spades})
+	if _, err := Evaluate(invalid...); err != ErrInvalidCard {
+		t.Errorf("got error %v, want %v", err, ErrInvalidCard)
+	}
+	if _, err := EvaluateWild(append(invalid, deck.Card{Suit: deck.Joker})...); err != ErrInvalidCard {
+		t.Errorf("wild: got error %v, want %v", err, ErrInvalidCard)
+	}
+}
+
+func TestEvaluateWild(t *testing.T) {
+	testCases := []struct {
+		cards   string
+		wantCat Category
+		wantTop deck.Rank
+	}{
+		{"AS AD AC AH Joker", FiveOfAKind, deck.Ace},
+		{"9C 10C JC QC Joker", StraightFlush, deck.King},
+		{"2D 7S 9H QC Joker", OnePair, deck.Queen},
+		{"2D 3D 9H QC Joker Joker1", ThreeOfAKind, deck.Queen},
+		{"2D 3D Joker Joker1 Joker2 Joker3 Joker", FiveOfAKind, deck.Ace},
+		{"AH Joker Joker1 Joker2 Joker3", FiveOfAKind, deck.Ace},
+		{"2D 9H Joker Joker1 Joker2", FourOfAKind, deck.Nine},
+		{"10C KC Joker Joker1 Joker2", StraightFlush, deck.Ace},
+	}
+	for _, tc := range testCases {
+		h, err := EvaluateWild(cards(t, tc.cards)...)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.cards, err)
+		}
+		if h.Category != tc.wantCat || h.Ranks[0] != tc.wantTop {
+			t.Errorf("%s: got %s, want %s (%s high)", tc.cards, h, tc.wantCat, tc.wantTop)
+		}
+	}
+}
+
+// TestEvaluateWildEveryCard checks EvaluateWild against trying every card in place of each
+// joker.
+func TestEvaluateWildEveryCard(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	joker := deck.Card{Suit: deck.Joker}
+	for n := 0; n < 300; n++ {
+		size := minCards + r.Intn(maxCards-minCards+1)
+		jokers := 1 + r.Intn(2)
+		hand := deck.New(deck.ShuffleWith(r))[:size-jokers]
+		got, err := EvaluateWild(append(hand, joker, joker)[:size]...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := everyCard(hand, jokers); got != want {
+			t.Fatalf("%v with %d jokers: got %s %v, want %s %v", hand, jokers, got, got.Ranks, want, want.Ranks)
+		}
+	}
+}
+
+// everyCard returns the best hand made by putting every card in place of each joker.
+func everyCard(hand []deck.Card, jokers int) Hand {
+	if jokers == 0 {
+		h, _ := Evaluate(hand...)
+		return h
+	}
+	var best Hand
+	for _, suit := range pokerSuits {
+		for r := deck.Ace; r <= deck.King; r++ {
+			if h := everyCard(append(hand[:len(hand):len(hand)], deck.Card{Rank: r, Suit: suit}), jokers-1); Compare(h, best) > 0 {
+				best = h
+			}
+		}
+	}
+	return best
+}
+
+// TestEvaluateSevenCards checks that evaluating seven cards finds the best of the 21 five-card
+// hands they contain.
+func TestEvaluateSevenCards(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	for n := 0; n < 2000; n++ {
+		seven := deck.New(deck.ShuffleWith(r))[:7]
+		got, err := Evaluate(seven...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want Hand
+		for i := 0; i < 7; i++ {
+			for j := i + 1; j < 7; j++ {
+				var five []deck.Card
+				for k, c := range seven {
+					if k != i && k != j {
+						five = append(five, c)
+					}
+				}
+				h, _ := Evaluate(five...)
+				if Compare(h, want) > 0 {
+					want = h
+				}
+			}
+		}
+		if got != want {
+			t.Fatalf("%v: got %s %v, want %s %v", seven, got, got.Ranks, want, want.Ranks)
+		}
+	}
+}
+
+func BenchmarkEvaluate(b *testing.B) {
+	r := rand.New(rand.NewSource(0))
+	hands := make([][]deck.Card, 1024)
+	for i := range hands {
+		hands[i] = deck.New(deck.ShuffleWith(r))[:7]
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Evaluate(hands[i%len(hands)]...)
+	}
+}
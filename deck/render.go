@@ -0,0 +1,151 @@
+package deck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GlyphBack is the Unicode playing card showing the back of a card.
+const GlyphBack = "\U0001F0A0"
+
+// Unicode playing cards are laid out in one block per suit, with the ace of each suit at the
+// block's base plus one and a Knight between Jack and Queen, which standard decks don't use.
+var glyphSuitBase = [...]rune{
+	Spades:   0x1F0A0,
+	Hearts:   0x1F0B0,
+	Diamonds: 0x1F0C0,
+	Clubs:    0x1F0D0,
+}
+
+// glyphJokers holds the Unicode black, red and white Jokers, used in turn for Jokers of each rank.
+var glyphJokers = [...]rune{0x1F0CF, 0x1F0BF, 0x1F0DF}
+
+// Glyph returns the card as a single Unicode playing card character, e.g. "🂡" for the Ace of
+// Spades.
+func (c Card) Glyph() string {
+	if c.Suit == Joker {
+		return string(glyphJokers[int(c.Rank)%len(glyphJokers)])
+	}
+	if !c.valid() {
+		return c.String()
+	}
+	offset := rune(c.Rank)
+	if c.Rank >= Queen {
+		offset++ // skip the Knight
+	}
+	return string(glyphSuitBase[c.Suit] + offset)
+}
+
+var suitSymbols = [...]string{
+	Spades:   "♠",
+	Diamonds: "♦",
+	Clubs:    "♣",
+	Hearts:   "♥",
+	Joker:    "★",
+}
+
+// ANSI escape sequences used to colour red suits.
+const (
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+// A Renderer draws cards for display in a terminal.
+type Renderer struct {
+	// Colour draws Hearts and Diamonds in red using ANSI escape sequences.
+	Colour bool
+}
+
+// Glyphs returns the cards as Unicode playing card characters separated by spaces.
+func (r Renderer) Glyphs(cards ...Card) string {
+	return r.HiddenGlyphs(cards, 0)
+}
+
+// HiddenGlyphs returns the face-up cards as Unicode playing card characters, followed by down
+// cards shown face down, all separated by spaces.
+func (r Renderer) HiddenGlyphs(up []Card, down int) string {
+	glyphs := make([]string, 0, len(up)+down)
+	for _, c := range up {
+		glyphs = append(glyphs, r.colour(c, c.Glyph()))
+	}
+	for i := 0; i < down; i++ {
+		glyphs = append(glyphs, GlyphBack)
+	}
+	return strings.Join(glyphs, " ")
+}
+
+// Art returns the cards drawn side by side as ASCII-art boxes, spanning several lines.
+func (r Renderer) Art(cards ...Card) string {
+	return r.HiddenArt(cards, 0)
+}
+
+// HiddenArt returns the face-up cards drawn side by side as ASCII-art boxes, followed by down cards
+// shown face down.
+func (r Renderer) HiddenArt(up []Card, down int) string {
+	lines := make([]string, artHeight)
+	for i, c := range up {
+		art := cardArt(c)
+		for l := range lines {
+			lines[l] += separator(i) + r.colour(c, art[l])
+		}
+	}
+	for i := 0; i < down; i++ {
+		for l := range lines {
+			lines[l] += separator(len(up)+i) + faceDownArt[l]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// separator returns the space drawn before the ith card in a row.
+func separator(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return " "
+}
+
+// colour wraps s in the ANSI colour of the card's suit, if the Renderer uses colour.
+func (r Renderer) colour(c Card, s string) string {
+	if r.Colour && (c.Suit == Hearts || c.Suit == Diamonds) {
+		return ansiRed + s + ansiReset
+	}
+	return s
+}
+
+const artHeight = 5
+
+var faceDownArt = [artHeight]string{
+	"+-----+",
+	"|#####|",
+	"|#####|",
+	"|#####|",
+	"+-----+",
+}
+
+// invalidArt is drawn in place of a card with no valid rank or suit.
+var invalidArt = [artHeight]string{
+	"+-----+",
+	"|?    |",
+	"|  ?  |",
+	"|    ?|",
+	"+-----+",
+}
+
+// cardArt returns the lines of an ASCII-art box showing the card's rank and suit.
+func cardArt(c Card) [artHeight]string {
+	if !c.valid() {
+		return invalidArt
+	}
+	label := "JK"
+	if c.Suit != Joker {
+		label = rankNotation[c.Rank]
+	}
+	return [artHeight]string{
+		"+-----+",
+		fmt.Sprintf("|%-2s   |", label),
+		fmt.Sprintf("|  %s  |", suitSymbols[c.Suit]),
+		fmt.Sprintf("|   %2s|", label),
+		"+-----+",
+	}
+}
@@ -0,0 +1,54 @@
+package deck
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleRenderer_Art() {
+	var r Renderer
+	fmt.Println(r.Art(Card{Rank: Ace, Suit: Spades}, Card{Rank: Ten, Suit: Hearts}))
+	fmt.Println(r.HiddenArt([]Card{{Rank: King, Suit: Clubs}}, 1))
+
+	// Output:
+	// +-----+ +-----+
+	// |A    | |10   |
+	// |  ♠  | |  ♥  |
+	// |    A| |   10|
+	// +-----+ +-----+
+	// +-----+ +-----+
+	// |K    | |#####|
+	// |  ♣  | |#####|
+	// |    K| |#####|
+	// +-----+ +-----+
+}
+
+func ExampleRenderer_Glyphs() {
+	var r Renderer
+	fmt.Println(r.Glyphs(Card{Rank: Ace, Suit: Spades}, Card{Rank: Queen, Suit: Hearts}, Card{Suit: Joker}))
+	fmt.Println(r.HiddenGlyphs([]Card{{Rank: Two, Suit: Diamonds}}, 1))
+
+	// Output:
+	// 🂡 🂽 🃏
+	// 🃂 🂠
+}
+
+func TestRendererColour(t *testing.T) {
+	r := Renderer{Colour: true}
+	if got := r.Glyphs(Card{Rank: Ace, Suit: Hearts}); !strings.HasPrefix(got, ansiRed) {
+		t.Errorf("red card %q not coloured red", got)
+	}
+	if got := r.Glyphs(Card{Rank: Ace, Suit: Spades}); strings.Contains(got, ansiRed) {
+		t.Errorf("black card %q coloured red", got)
+	}
+}
+
+func TestArtInvalidCard(t *testing.T) {
+	var r Renderer
+	for _, c := range []Card{{Rank: 0, Suit: Spades}, {Rank: King + 1, Suit: Hearts}, {Rank: Ace, Suit: Joker + 1}} {
+		if got, want := r.Art(c), strings.Join(invalidArt[:], "\n"); got != want {
+			t.Errorf("%#v drawn as\n%s\nwant\n%s", c, got, want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package deck
+
+// A Scorer assigns a point value to a hand of cards under the rules of a particular game.
+type Scorer interface {
+	Score(hand ...Card) int
+}
+
+// ScorerFunc adapts an ordinary function to the Scorer interface.
+type ScorerFunc func(hand ...Card) int
+
+// Score returns f(hand...).
+func (f ScorerFunc) Score(hand ...Card) int {
+	return f(hand...)
+}
+
+// pipValue returns the point value of a rank in games where court cards count as ten and aces as
+// one. Jokers are worth nothing.
+func pipValue(c Card) int {
+	if c.Suit == Joker {
+		return 0
+	}
+	if c.Rank > Ten {
+		return 10
+	}
+	return int(c.Rank)
+}
+
+// BlackjackScorer scores hands of blackjack, where court cards are worth ten and aces are worth
+// eleven unless that would bust the hand, in which case they are worth one.
+type BlackjackScorer struct{}
+
+// Score returns the point value of the hand, converting aces between 11 and 1 as appropriate.
+func (s BlackjackScorer) Score(hand ...Card) int {
+	minScore := s.MinScore(hand...)
+	if minScore > 11 {
+		return minScore
+	}
+	for _, c := range hand {
+		if c.Rank == Ace && c.Suit != Joker {
+			return minScore - 1 + 11 // ace is currently worth 1; change it to be worth 11
+		}
+	}
+	return minScore
+}
+
+// MinScore returns the point value of the hand, with any aces counted as 1.
+func (s BlackjackScorer) MinScore(hand ...Card) int {
+	var score int
+	for _, c := range hand {
+		score += pipValue(c)
+	}
+	return score
+}
+
+// Soft returns true if the score of the hand is a soft score. I.e. an ace is being counted as 11.
+func (s BlackjackScorer) Soft(hand ...Card) bool {
+	return s.MinScore(hand...) != s.Score(hand...)
+}
+
+// BaccaratScorer scores hands of baccarat, where tens and court cards are worth nothing, aces are
+// worth one, and only the last digit of the total counts.
+type BaccaratScorer struct{}
+
+// Score returns the point value of the hand, from 0 to 9.
+func (s BaccaratScorer) Score(hand ...Card) int {
+	var score int
+	for _, c := range hand {
+		if c.Rank < Ten {
+			score += pipValue(c)
+		}
+	}
+	return score % 10
+}
+
+// CribbageScorer scores the fifteens, pairs and runs in a cribbage hand. To score a hand with the
+// starter card, include the starter in the cards passed to Score. Flushes and nobs depend on which
+// card is the starter and are not scored.
+type CribbageScorer struct{}
+
+// Points awarded in cribbage.
+const (
+	cribbageFifteen = 2
+	cribbagePair    = 2
+)
+
+// Score returns the total points for fifteens, pairs and runs in the hand.
+func (s CribbageScorer) Score(hand ...Card) int {
+	return s.Fifteens(hand...) + s.Pairs(hand...) + s.Runs(hand...)
+}
+
+// Fifteens returns two points for every combination of cards whose values total fifteen, with
+// court cards counting as ten and aces as one.
+func (s CribbageScorer) Fifteens(hand ...Card) int {
+	var score int
+	for subset := 1; subset < 1<<uint(len(hand)); subset++ {
+		var total int
+		for i, c := range hand {
+			if subset&(1<<uint(i)) != 0 {
+				total += pipValue(c)
+			}
+		}
+		if total == 15 {
+			score += cribbageFifteen
+		}
+	}
+	return score
+}
+
+// Pairs returns two points for every pair of cards of the same rank.
+func (s CribbageScorer) Pairs(hand ...Card) int {
+	counts := rankCounts(hand)
+	var score int
+	for _, n := range counts {
+		score += cribbagePair * n * (n - 1) / 2
+	}
+	return score
+}
+
+// Runs returns a point for each card in every run of three or more consecutive ranks, counting
+// each distinct run separately, so a double run of three scores six.
+func (s CribbageScorer) Runs(hand ...Card) int {
+	counts := rankCounts(hand)
+	var score int
+	for start := minRank; start <= maxRank; {
+		if counts[start] == 0 {
+			start++
+			continue
+		}
+		length, combinations := 0, 1
+		end := start
+		for ; end <= maxRank && counts[end] > 0; end++ {
+			length++
+			combinations *= counts[end]
+		}
+		if length >= 3 {
+			score += length * combinations
+		}
+		start = end
+	}
+	return score
+}
+
+// rankCounts returns the number of cards of each rank in hand, ignoring Jokers.
+func rankCounts(hand []Card) [maxRank + 1]int {
+	var counts [maxRank + 1]int
+	for _, c := range hand {
+		if c.Suit != Joker {
+			counts[c.Rank]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,53 @@
+package deck
+
+import "testing"
+
+// hand parses cards in short notation, failing the test on error.
+func hand(t *testing.T, cards ...string) []Card {
+	t.Helper()
+	ret := make([]Card, len(cards))
+	for i, s := range cards {
+		c, err := ParseCard(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret[i] = c
+	}
+	return ret
+}
+
+func TestScorers(t *testing.T) {
+	testCases := []struct {
+		name   string
+		scorer Scorer
+		hand   []string
+		want   int
+	}{
+		{"blackjack soft", BlackjackScorer{}, []string{"AS", "6D"}, 17},
+		{"blackjack hard", BlackjackScorer{}, []string{"AS", "6D", "KH"}, 17},
+		{"blackjack two aces", BlackjackScorer{}, []string{"AS", "AD"}, 12},
+		{"blackjack", BlackjackScorer{}, []string{"AS", "QC"}, 21},
+		{"baccarat natural", BaccaratScorer{}, []string{"4S", "5D"}, 9},
+		{"baccarat court cards", BaccaratScorer{}, []string{"KS", "10D", "7C"}, 7},
+		{"baccarat wraps", BaccaratScorer{}, []string{"8S", "6D"}, 4},
+		{"cribbage 29 hand less nobs", CribbageScorer{}, []string{"5S", "5D", "5C", "JH", "5H"}, 28},
+		{"cribbage double run", CribbageScorer{}, []string{"3S", "4D", "5C", "5H", "KS"}, 12},
+		{"cribbage nothing", CribbageScorer{}, []string{"AS", "3D", "7C", "9H", "QS"}, 0},
+		{"func", ScorerFunc(func(hand ...Card) int { return len(hand) }), []string{"AS", "2S"}, 2},
+	}
+	for _, tc := range testCases {
+		if got := tc.scorer.Score(hand(t, tc.hand...)...); got != tc.want {
+			t.Errorf("%s: %v scored %d, want %d", tc.name, tc.hand, got, tc.want)
+		}
+	}
+}
+
+func TestBlackjackScorerSoft(t *testing.T) {
+	var s BlackjackScorer
+	if !s.Soft(hand(t, "AS", "6D")...) {
+		t.Error("A6 should be soft")
+	}
+	if s.Soft(hand(t, "AS", "6D", "KH")...) {
+		t.Error("A6K should be hard")
+	}
+}
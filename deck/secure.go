@@ -0,0 +1,145 @@
+package deck
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SecureShuffle is an Option returning a deck shuffled using the Fisher-Yates shuffle with
+// randomness from crypto/rand. Every ordering of the deck is equally likely. SecureShuffle panics
+// if the operating system's random number generator fails.
+func SecureShuffle(cards []Card) []Card {
+	if err := shuffleFrom(cards, rand.Reader); err != nil {
+		panic(fmt.Sprintf("deck: secure shuffle: %v", err))
+	}
+	return cards
+}
+
+// shuffleFrom shuffles cards in place using the Fisher-Yates shuffle, reading randomness from r.
+func shuffleFrom(cards []Card, r io.Reader) error {
+	for i := len(cards) - 1; i > 0; i-- {
+		swapTo, err := uniform(r, uint64(i+1))
+		if err != nil {
+			return err
+		}
+		cards[i], cards[swapTo] = cards[swapTo], cards[i]
+	}
+	return nil
+}
+
+// uniform returns a number in [0, n) read from r without modulo bias, by rejecting any values
+// from the incomplete final multiple of n below 2^64.
+func uniform(r io.Reader, n uint64) (uint64, error) {
+	limit := ^uint64(0) - ^uint64(0)%n
+	var buf [8]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, err
+		}
+		if v := binary.BigEndian.Uint64(buf[:]); v < limit {
+			return v % n, nil
+		}
+	}
+}
+
+// seedSize is the number of random bytes in a CommittedShuffle seed.
+const seedSize = 32
+
+// A CommittedShuffle supports provably fair dealing by commit-reveal. Before dealing, the dealer
+// publishes the Commitment, a SHA-256 hash of a secret random seed. The deck is shuffled
+// deterministically from the seed, which is revealed after play so that anyone can confirm with
+// Verify that the seed matches the commitment and produces the order in which cards were dealt.
+type CommittedShuffle struct {
+	seed [seedSize]byte
+}
+
+// NewCommittedShuffle returns a CommittedShuffle with a new secret seed from crypto/rand.
+func NewCommittedShuffle() (*CommittedShuffle, error) {
+	var s CommittedShuffle
+	if _, err := io.ReadFull(rand.Reader, s.seed[:]); err != nil {
+		return nil, fmt.Errorf("deck: generating shuffle seed: %w", err)
+	}
+	return &s, nil
+}
+
+// Commitment returns the SHA-256 hash of the seed, to be published before dealing.
+func (s *CommittedShuffle) Commitment() [sha256.Size]byte {
+	return sha256.Sum256(s.seed[:])
+}
+
+// Shuffle is an Option that shuffles a deck deterministically from the seed. Every ordering of the
+// deck is equally likely.
+func (s *CommittedShuffle) Shuffle(cards []Card) []Card {
+	// A seedStream never fails to read.
+	shuffleFrom(cards, newSeedStream(s.seed[:]))
+	return cards
+}
+
+// Reveal returns the seed, to be published after dealing so that the shuffle can be verified.
+func (s *CommittedShuffle) Reveal() []byte {
+	ret := make([]byte, seedSize)
+	copy(ret, s.seed[:])
+	return ret
+}
+
+var (
+	// ErrCommitmentMismatch is returned by Verify when a seed doesn't hash to the commitment.
+	ErrCommitmentMismatch = errors.New("deck: seed does not match commitment")
+	// ErrShuffleMismatch is returned by Verify when a seed doesn't produce the dealt order.
+	ErrShuffleMismatch = errors.New("deck: seed does not produce the shuffled deck")
+)
+
+// Verify checks that seed is the one committed to by commitment, and that shuffling the unshuffled
+// deck with a CommittedShuffle using the seed produces shuffled. unshuffled is not modified.
+func Verify(commitment [sha256.Size]byte, seed []byte, unshuffled, shuffled []Card) error {
+	hash := sha256.Sum256(seed)
+	if subtle.ConstantTimeCompare(hash[:], commitment[:]) != 1 {
+		return ErrCommitmentMismatch
+	}
+	if len(unshuffled) != len(shuffled) {
+		return ErrShuffleMismatch
+	}
+	cards := make([]Card, len(unshuffled))
+	copy(cards, unshuffled)
+	shuffleFrom(cards, newSeedStream(seed))
+	for i := range cards {
+		if cards[i] != shuffled[i] {
+			return ErrShuffleMismatch
+		}
+	}
+	return nil
+}
+
+// seedStream is an endless stream of pseudorandom bytes derived from a seed by hashing it with
+// SHA-256 in counter mode.
+type seedStream struct {
+	seed    []byte
+	counter uint64
+	block   []byte // unread bytes of the current block
+}
+
+func newSeedStream(seed []byte) *seedStream {
+	return &seedStream{seed: seed}
+}
+
+func (s *seedStream) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(s.block) == 0 {
+			h := sha256.New()
+			h.Write(s.seed)
+			binary.Write(h, binary.BigEndian, s.counter)
+			s.counter++
+			s.block = h.Sum(nil)
+		}
+		copied := copy(p[n:], s.block)
+		s.block = s.block[copied:]
+		n += copied
+	}
+	return n, nil
+}
@@ -0,0 +1,59 @@
+package deck
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSecureShuffle(t *testing.T) {
+	cards := New(SecureShuffle)
+	if len(cards) != cardsInDeck {
+		t.Fatalf("deck has len %d, want %d", len(cards), cardsInDeck)
+	}
+	seen := make(map[Card]bool)
+	for _, card := range cards {
+		if seen[card] {
+			t.Fatalf("%s appears twice in shuffled deck", card)
+		}
+		seen[card] = true
+	}
+}
+
+func TestUniform(t *testing.T) {
+	// The first value read is rejected because it falls beyond the last multiple of 3 below 2^64.
+	r := bytes.NewReader([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05,
+	})
+	got, err := uniform(r, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 2 {
+		t.Errorf("got %d, want 2", got)
+	}
+}
+
+func TestCommittedShuffle(t *testing.T) {
+	cs, err := NewCommittedShuffle()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	commitment := cs.Commitment()
+	shuffled := New(cs.Shuffle)
+
+	if err := Verify(commitment, cs.Reveal(), New(), shuffled); err != nil {
+		t.Errorf("verifying genuine shuffle: %v", err)
+	}
+
+	seed := cs.Reveal()
+	seed[0]++
+	if err := Verify(commitment, seed, New(), shuffled); err != ErrCommitmentMismatch {
+		t.Errorf("verifying with the wrong seed: got %v, want %v", err, ErrCommitmentMismatch)
+	}
+
+	shuffled[0], shuffled[1] = shuffled[1], shuffled[0]
+	if err := Verify(commitment, cs.Reveal(), New(), shuffled); err != ErrShuffleMismatch {
+		t.Errorf("verifying tampered deck: got %v, want %v", err, ErrShuffleMismatch)
+	}
+}
@@ -0,0 +1,212 @@
+package deck
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyShoe is returned when cards are drawn from a Shoe with too few cards remaining.
+var ErrEmptyShoe = errors.New("deck: not enough cards left in shoe")
+
+// noCutCard marks a Shoe whose cut card has not been placed.
+const noCutCard = -1
+
+// Shoe is a dealing shoe holding the cards yet to be dealt, a cut card marking when the shoe
+// should be reshuffled, and the discard pile of cards that have been played.
+type Shoe struct {
+	cards        []Card
+	next         int // index in cards of the next card to be dealt
+	cut          int // index in cards of the card in front of which the cut card sits
+	discards     []Card
+	decks        []int // the deck each card in cards came from, if the shoe holds marked cards
+	discardDecks []int // the deck each card in discards came from, if the shoe holds marked cards
+}
+
+// NewShoe returns a Shoe that deals cards in order, starting with cards[0]. Typically cards is
+// a deck returned by New with a shuffle Option.
+func NewShoe(cards []Card) *Shoe {
+	s := &Shoe{
+		cards: make([]Card, len(cards)),
+		cut:   noCutCard,
+	}
+	copy(s.cards, cards)
+	return s
+}
+
+// NewMarkedShoe returns a Shoe that deals marked cards in order, starting with cards[0], keeping
+// track of the deck each came from. Typically cards is a deck returned by Mark and shuffled with
+// Apply.
+func NewMarkedShoe(cards []Marked) *Shoe {
+	s := NewShoe(Unmark(cards))
+	s.decks = make([]int, len(cards))
+	for i, m := range cards {
+		s.decks[i] = m.Deck
+	}
+	return s
+}
+
+// Draw deals the next card from the shoe, returning ErrEmptyShoe if the shoe is empty.
+func (s *Shoe) Draw() (Card, error) {
+	if s.Remaining() == 0 {
+		return Card{}, ErrEmptyShoe
+	}
+	card := s.cards[s.next]
+	s.next++
+	return card, nil
+}
+
+// DrawMarked deals the next card from the shoe marked with the deck it came from, returning
+// ErrEmptyShoe if the shoe is empty. Cards in a shoe made by NewShoe are marked deck 0.
+func (s *Shoe) DrawMarked() (Marked, error) {
+	i := s.next
+	card, err := s.Draw()
+	if err != nil {
+		return Marked{}, err
+	}
+	m := Marked{Card: card}
+	if s.decks != nil {
+		m.Deck = s.decks[i]
+	}
+	return m, nil
+}
+
+// DrawN deals the next n cards from the shoe. If fewer than n cards remain, no cards are dealt
+// and ErrEmptyShoe is returned.
+func (s *Shoe) DrawN(n int) ([]Card, error) {
+	if n > s.Remaining() {
+		return nil, ErrEmptyShoe
+	}
+	cards := make([]Card, n)
+	copy(cards, s.cards[s.next:s.next+n])
+	s.next += n
+	return cards, nil
+}
+
+// Burn deals the next card straight to the discard pile, returning the card burned.
+func (s *Shoe) Burn() (Card, error) {
+	card, err := s.Draw()
+	if err != nil {
+		return Card{}, err
+	}
+	s.Discard(card)
+	return card, nil
+}
+
+// Discard adds cards that have finished being played to the discard pile. In a shoe made by
+// NewMarkedShoe, they are marked deck 0; use DiscardMarked to keep their marks.
+func (s *Shoe) Discard(cards ...Card) {
+	s.discards = append(s.discards, cards...)
+	if s.decks != nil {
+		s.discardDecks = append(s.discardDecks, make([]int, len(cards))...)
+	}
+}
+
+// DiscardMarked adds marked cards that have finished being played to the discard pile, keeping
+// their marks if the shoe was made by NewMarkedShoe.
+func (s *Shoe) DiscardMarked(cards ...Marked) {
+	for _, m := range cards {
+		s.discards = append(s.discards, m.Card)
+		if s.decks != nil {
+			s.discardDecks = append(s.discardDecks, m.Deck)
+		}
+	}
+}
+
+// Discards returns a copy of the discard pile, in the order the cards were discarded.
+func (s *Shoe) Discards() []Card {
+	ret := make([]Card, len(s.discards))
+	copy(ret, s.discards)
+	return ret
+}
+
+// Collect gathers the shoe's cards up for reshuffling: the discard pile, in the order the cards
+// were discarded, followed by the cards left to be dealt. Cards dealt but not yet discarded are
+// left out.
+func (s *Shoe) Collect() []Card {
+	ret := make([]Card, 0, len(s.discards)+s.Remaining())
+	ret = append(ret, s.discards...)
+	return append(ret, s.cards[s.next:]...)
+}
+
+// CollectMarked gathers the shoe's cards up for reshuffling as Collect does, keeping the marks of
+// a shoe made by NewMarkedShoe so that they survive the reshuffle, e.g.
+//
+//	shoe = NewMarkedShoe(Apply(shoe.CollectMarked(), Shuffle))
+//
+// Cards in a shoe made by NewShoe are marked deck 0.
+func (s *Shoe) CollectMarked() []Marked {
+	ret := make([]Marked, 0, len(s.discards)+s.Remaining())
+	for i, card := range s.discards {
+		m := Marked{Card: card}
+		if s.decks != nil {
+			m.Deck = s.discardDecks[i]
+		}
+		ret = append(ret, m)
+	}
+	for i := s.next; i < len(s.cards); i++ {
+		m := Marked{Card: s.cards[i]}
+		if s.decks != nil {
+			m.Deck = s.decks[i]
+		}
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+// PlaceCutCard places the cut card so that it is reached once n cards have been dealt from a
+// full shoe.
+func (s *Shoe) PlaceCutCard(n int) error {
+	if n < 0 || n > len(s.cards) {
+		return fmt.Errorf("deck: cut card position %d outside shoe of %d cards", n, len(s.cards))
+	}
+	s.cut = n
+	return nil
+}
+
+// CutCardReached reports whether the cut card has been placed and dealing has reached it,
+// signalling that the shoe should be reshuffled after the current hand.
+func (s *Shoe) CutCardReached() bool {
+	return s.cut != noCutCard && s.next >= s.cut
+}
+
+// Len returns the total number of cards the shoe was loaded with.
+func (s *Shoe) Len() int {
+	return len(s.cards)
+}
+
+// Remaining returns the number of cards left to be dealt.
+func (s *Shoe) Remaining() int {
+	return len(s.cards) - s.next
+}
+
+// Dealt returns the number of cards dealt from the shoe, including burned cards.
+func (s *Shoe) Dealt() int {
+	return s.next
+}
+
+// Penetration returns the percentage of the shoe that has been dealt, from 0 to 100.
+func (s *Shoe) Penetration() float64 {
+	if len(s.cards) == 0 {
+		return 0
+	}
+	return 100 * float64(s.next) / float64(len(s.cards))
+}
+
+// Clone returns an independent copy of the shoe.
+func (s *Shoe) Clone() *Shoe {
+	ret := &Shoe{
+		cards:    make([]Card, len(s.cards)),
+		next:     s.next,
+		cut:      s.cut,
+		discards: make([]Card, len(s.discards)),
+	}
+	copy(ret.cards, s.cards)
+	copy(ret.discards, s.discards)
+	if s.decks != nil {
+		ret.decks = make([]int, len(s.decks))
+		copy(ret.decks, s.decks)
+		ret.discardDecks = make([]int, len(s.discardDecks))
+		copy(ret.discardDecks, s.discardDecks)
+	}
+	return ret
+}
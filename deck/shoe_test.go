@@ -0,0 +1,152 @@
+package deck
+
+import "testing"
+
+func TestShoeDraw(t *testing.T) {
+	cards := New()
+	shoe := NewShoe(cards)
+	for i := 0; i < cardsInDeck; i++ {
+		card, err := shoe.Draw()
+		if err != nil {
+			t.Fatalf("draw %d: unexpected error: %v", i+1, err)
+		}
+		if card != cards[i] {
+			t.Fatalf("draw %d: got %s, want %s", i+1, card, cards[i])
+		}
+	}
+	if _, err := shoe.Draw(); err != ErrEmptyShoe {
+		t.Errorf("drawing from an empty shoe returned error %v, want %v", err, ErrEmptyShoe)
+	}
+}
+
+func TestShoeDrawN(t *testing.T) {
+	shoe := NewShoe(New())
+	if _, err := shoe.DrawN(cardsInDeck - 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := shoe.DrawN(3); err != ErrEmptyShoe {
+		t.Errorf("got error %v, want %v", err, ErrEmptyShoe)
+	}
+	if shoe.Remaining() != 2 {
+		t.Errorf("a failed DrawN left %d cards in the shoe, want 2", shoe.Remaining())
+	}
+}
+
+func TestShoeBurn(t *testing.T) {
+	shoe := NewShoe(New())
+	burned, err := shoe.Burn()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	discards := shoe.Discards()
+	if len(discards) != 1 || discards[0] != burned {
+		t.Errorf("discard pile is %v, want [%s]", discards, burned)
+	}
+	if shoe.Dealt() != 1 {
+		t.Errorf("shoe has dealt %d cards, want 1", shoe.Dealt())
+	}
+}
+
+func TestShoeCutCard(t *testing.T) {
+	shoe := NewShoe(New())
+	if shoe.CutCardReached() {
+		t.Fatal("cut card reached before it was placed")
+	}
+	if err := shoe.PlaceCutCard(cardsInDeck + 1); err == nil {
+		t.Error("expected an error placing the cut card outside the shoe")
+	}
+	if err := shoe.PlaceCutCard(39); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shoe.DrawN(38)
+	if shoe.CutCardReached() {
+		t.Error("cut card reached one card early")
+	}
+	shoe.Draw()
+	if !shoe.CutCardReached() {
+		t.Error("cut card not reached")
+	}
+	if got, want := shoe.Penetration(), 75.0; got != want {
+		t.Errorf("penetration is %.2f%%, want %.2f%%", got, want)
+	}
+}
+
+func TestShoeCollect(t *testing.T) {
+	cards := New()
+	shoe := NewShoe(cards)
+	dealt, err := shoe.DrawN(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shoe.Discard(dealt[2], dealt[0])
+	got := shoe.Collect()
+	want := append([]Card{dealt[2], dealt[0]}, cards[3:]...)
+	if len(got) != len(want) {
+		t.Fatalf("collected %d cards, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("card %d is %s, want %s", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestShoeDrawMarked(t *testing.T) {
+	cards := Apply(Mark(New(Deck(2))), SeededShuffle(0))
+	shoe := NewMarkedShoe(cards)
+	if _, err := shoe.DrawN(3); err != nil {
+		t.Fatal(err)
+	}
+	clone := shoe.Clone()
+	for _, s := range []*Shoe{shoe, clone} {
+		got, err := s.DrawMarked()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != cards[3] {
+			t.Errorf("drew %s, want %s", got, cards[3])
+		}
+	}
+
+	if got, _ := NewShoe(New()).DrawMarked(); got.Deck != 0 {
+		t.Errorf("unmarked shoe dealt a card from deck %d, want 0", got.Deck)
+	}
+	if _, err := NewMarkedShoe(nil).DrawMarked(); err != ErrEmptyShoe {
+		t.Errorf("got error %v, want %v", err, ErrEmptyShoe)
+	}
+}
+
+func TestShoeCollectMarked(t *testing.T) {
+	cards := Apply(Mark(New(Deck(2))), SeededShuffle(0))
+	shoe := NewMarkedShoe(cards)
+	var dealt []Marked
+	for i := 0; i < 3; i++ {
+		m, err := shoe.DrawMarked()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealt = append(dealt, m)
+	}
+	shoe.DiscardMarked(dealt[2], dealt[0], dealt[1])
+	got := shoe.Clone().CollectMarked()
+	want := append([]Marked{dealt[2], dealt[0], dealt[1]}, cards[3:]...)
+	if len(got) != len(want) {
+		t.Fatalf("collected %d cards, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("card %d is %s, want %s", i+1, got[i], want[i])
+		}
+	}
+
+	// Marks survive reshuffling the collected cards into a new shoe.
+	shoe = NewMarkedShoe(Apply(shoe.CollectMarked(), SeededShuffle(1)))
+	var reshuffled []Marked
+	for shoe.Remaining() > 0 {
+		m, _ := shoe.DrawMarked()
+		reshuffled = append(reshuffled, m)
+	}
+	if dups := Duplicates(reshuffled); len(dups) != 0 {
+		t.Errorf("reshuffled shoe has duplicates %v", dups)
+	}
+}
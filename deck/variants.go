@@ -0,0 +1,56 @@
+package deck
+
+// Options converting a standard 52-card deck into the decks used by other games. Apply them to
+// New before any Option that shuffles or multiplies the deck; Jokers are left untouched. Applied to
+// an unshuffled deck, each leaves the cards in DefaultSort order.
+
+// Numbers of cards in the variant decks.
+const (
+	cardsInSpanishDeck  = 48
+	cardsInPinochleDeck = 48
+	cardsInEuchreDeck   = 24
+	cardsInPiquetDeck   = 32
+)
+
+// Spanish is an Option that removes the Tens from a deck, leaving the 48-card deck used in Spanish
+// 21.
+func Spanish(cards []Card) []Card {
+	return keepRanks(cards, 1, Ace, Two, Three, Four, Five, Six, Seven, Eight, Nine, Jack, Queen, King)
+}
+
+// Pinochle is an Option that converts a deck to the 48-card Pinochle deck, holding two of each Nine,
+// Ten, Jack, Queen, King and Ace.
+func Pinochle(cards []Card) []Card {
+	return keepRanks(cards, 2, Ace, Nine, Ten, Jack, Queen, King)
+}
+
+// Euchre is an Option that converts a deck to the 24-card Euchre deck of Nines through Aces.
+func Euchre(cards []Card) []Card {
+	return keepRanks(cards, 1, Ace, Nine, Ten, Jack, Queen, King)
+}
+
+// Piquet is an Option that converts a deck to the 32-card Piquet deck of Sevens through Aces.
+func Piquet(cards []Card) []Card {
+	return keepRanks(cards, 1, Ace, Seven, Eight, Nine, Ten, Jack, Queen, King)
+}
+
+// keepRanks returns n copies of each card in cards with one of the given ranks, discarding the
+// rest. Jokers are kept as they are.
+func keepRanks(cards []Card, n int, ranks ...Rank) []Card {
+	var keep [maxRank + 1]bool
+	for _, r := range ranks {
+		keep[r] = true
+	}
+	var ret []Card
+	for _, card := range cards {
+		switch {
+		case card.Suit == Joker:
+			ret = append(ret, card)
+		case keep[card.Rank]:
+			for i := 0; i < n; i++ {
+				ret = append(ret, card)
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,59 @@
+package deck
+
+import "testing"
+
+func TestVariants(t *testing.T) {
+	testCases := []struct {
+		name      string
+		opt       Option
+		wantLen   int
+		wantFirst Card
+		wantLast  Card
+	}{
+		{"Spanish", Spanish, cardsInSpanishDeck, Card{Ace, Spades}, Card{King, Hearts}},
+		{"Pinochle", Pinochle, cardsInPinochleDeck, Card{Ace, Spades}, Card{King, Hearts}},
+		{"Euchre", Euchre, cardsInEuchreDeck, Card{Ace, Spades}, Card{King, Hearts}},
+		{"Piquet", Piquet, cardsInPiquetDeck, Card{Ace, Spades}, Card{King, Hearts}},
+	}
+	for _, tc := range testCases {
+		cards := New(tc.opt)
+		if len(cards) != tc.wantLen {
+			t.Errorf("%s: deck has len %d, want %d", tc.name, len(cards), tc.wantLen)
+			continue
+		}
+		if cards[0] != tc.wantFirst || cards[len(cards)-1] != tc.wantLast {
+			t.Errorf("%s: deck runs from %s to %s, want %s to %s",
+				tc.name, cards[0], cards[len(cards)-1], tc.wantFirst, tc.wantLast)
+		}
+		sorted := DefaultSort(append([]Card(nil), cards...))
+		for i := range cards {
+			if cards[i] != sorted[i] {
+				t.Errorf("%s: card %d is %s, want %s in DefaultSort order", tc.name, i+1, cards[i], sorted[i])
+				break
+			}
+		}
+	}
+}
+
+func TestSpanishHasNoTens(t *testing.T) {
+	for _, card := range New(Spanish) {
+		if card.Rank == Ten {
+			t.Fatalf("found %s in a Spanish deck", card)
+		}
+	}
+}
+
+func TestPinochleDuplicates(t *testing.T) {
+	counts := make(map[Card]int)
+	for _, card := range New(Pinochle) {
+		counts[card]++
+	}
+	for card, n := range counts {
+		if n != 2 {
+			t.Errorf("found %d of %s, want 2", n, card)
+		}
+		if card.Rank != Ace && card.Rank < Nine {
+			t.Errorf("found %s in a Pinochle deck", card)
+		}
+	}
+}